@@ -0,0 +1,175 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// snippetContext is the number of lines shown above and below a boundary.
+const snippetContext = 2
+
+// funcDecl recognises the function or route handler declarations of one
+// language. The regex may name a "name" group, or "method" and "route"
+// groups for framework route registrations.
+type funcDecl struct {
+	re *regexp.Regexp
+	// indentScoped languages end a function body by dedenting, so only a
+	// declaration indented less than the boundary can enclose it.
+	indentScoped bool
+}
+
+var funcDecls = map[string]funcDecl{}
+
+// registerFuncDecl sets the declaration pattern used to find the enclosing
+// function of boundaries in files with the given extensions.
+func registerFuncDecl(exts []string, re *regexp.Regexp, indentScoped bool) {
+	for _, ext := range exts {
+		funcDecls[ext] = funcDecl{re, indentScoped}
+	}
+}
+
+// annotate fills in the enclosing function, code snippet, data flow and
+// severity of each boundary found in content, replaces the guessed sink
+// suggestions with the remediation for the sink it reaches, suggests the
+// format its name hints at, and defaults missing end positions to the start
+// and missing confidence to "medium".
+func annotate(bs []Boundary, content, ext string) {
+	lines := strings.Split(content, "\n")
+	decl, hasDecl := funcDecls[ext]
+	for i := range bs {
+		b := &bs[i]
+		if b.EndLine == 0 {
+			b.EndLine, b.EndColumn = b.Line, b.Column
+		}
+		if hasDecl {
+			b.Function = enclosingFunc(lines, b.Line, decl)
+		}
+		b.SnippetStart, b.Snippet = snippet(lines, b.Line, b.EndLine)
+		b.Sink, b.Guarded = traceFlow(lines, *b, decl, hasDecl)
+		if s, ok := sinkSanitizers[b.Sink]; ok {
+			b.Validation = sinkAdvice(b.Validation, s.advice)
+		}
+		// A format only the name suggests is left to the reader, not
+		// generated as a rule.
+		if f := SuggestFormat(b.Variable); f != "" && b.Type != "env_var" {
+			b.Validation = append(b.Validation[:len(b.Validation):len(b.Validation)],
+				"validate as "+f+" if it always is one (suggested by its name)")
+		}
+		if b.Confidence == "" {
+			b.Confidence = "medium"
+		}
+		b.Severity = scoreSeverity(*b)
+	}
+}
+
+// guessedSinkAdvice lists the suggestions genValidation and
+// genDotnetValidation make for the sink an input type usually reaches.
+var guessedSinkAdvice = map[string]bool{
+	"sanitize HTML entities":                               true,
+	"reject path traversal sequences":                      true,
+	"HtmlEncoder.Default.Encode before rendering":          true,
+	`[RegularExpression(@"^[\w-]+$")] to reject traversal`: true,
+}
+
+// sinkAdvice returns v with the guessed sink suggestions replaced by advice
+// for the sink the value actually reaches. v is not modified.
+func sinkAdvice(v []string, advice string) []string {
+	out := make([]string, 0, len(v)+1)
+	for _, s := range v {
+		if !guessedSinkAdvice[s] {
+			out = append(out, s)
+		}
+	}
+	return append(out, advice)
+}
+
+// enclosingFunc returns the name of the nearest declaration at or above line.
+func enclosingFunc(lines []string, line int, decl funcDecl) string {
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	indent := indentOf(lines[line-1])
+	for i := line - 1; i >= 0; i-- {
+		if decl.indentScoped && i != line-1 && strings.TrimSpace(lines[i]) != "" && indentOf(lines[i]) >= indent {
+			continue
+		}
+		if name := declName(decl.re, lines[i]); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// declKeywords are control-flow keywords that method-shaped patterns such as
+// "name(...) {" would otherwise mistake for declarations.
+var declKeywords = map[string]bool{
+	"if": true, "for": true, "while": true, "switch": true, "catch": true,
+	"return": true, "function": true, "foreach": true, "using": true, "lock": true,
+}
+
+func declName(re *regexp.Regexp, line string) string {
+	m := re.FindStringSubmatch(line)
+	if m == nil {
+		return ""
+	}
+	var name, method, route string
+	for i, n := range re.SubexpNames() {
+		if m[i] == "" {
+			continue
+		}
+		switch n {
+		case "name":
+			name = m[i]
+		case "method":
+			method = strings.ToUpper(m[i])
+		case "route":
+			route = m[i]
+		}
+	}
+	switch {
+	case name != "" && !declKeywords[name]:
+		return name
+	case route != "":
+		return strings.TrimSpace(method + " " + route)
+	}
+	return ""
+}
+
+// snippet returns the lines from start-snippetContext to end+snippetContext
+// with their common indentation and trailing whitespace removed, together with
+// the line number of the first returned line.
+func snippet(lines []string, start, end int) (int, string) {
+	if start < 1 || start > len(lines) {
+		return 0, ""
+	}
+	from := start - snippetContext
+	if from < 1 {
+		from = 1
+	}
+	to := end + snippetContext
+	if to > len(lines) {
+		to = len(lines)
+	}
+	window := make([]string, 0, to-from+1)
+	common := -1
+	for _, l := range lines[from-1 : to] {
+		l = strings.TrimRight(l, " \t\r")
+		window = append(window, l)
+		if l == "" {
+			continue
+		}
+		if n := indentOf(l); common < 0 || n < common {
+			common = n
+		}
+	}
+	for i, l := range window {
+		if len(l) >= common && common > 0 {
+			window[i] = l[common:]
+		}
+	}
+	// Drop trailing blank lines so the snippet ends with code.
+	for len(window) > 1 && window[len(window)-1] == "" {
+		window = window[:len(window)-1]
+	}
+	return from, strings.Join(window, "\n")
+}
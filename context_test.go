@@ -0,0 +1,81 @@
+package main
+
+import "testing"
+
+func TestBoundaryPositionAndFunction(t *testing.T) {
+	code := "package main\n\n" +
+		"func (s *Server) search(w http.ResponseWriter, r *http.Request) {\n" +
+		"\tq := r.URL.Query().Get(\"q\")\n" +
+		"\t_ = q\n" +
+		"}\n"
+	bs := ScanContent(code, "server.go", ".go")
+	if len(bs) != 1 {
+		t.Fatalf("want 1 boundary, got %d", len(bs))
+	}
+	b := bs[0]
+	if b.Line != 4 || b.Column != 9 || b.EndLine != 4 || b.EndColumn != 29 {
+		t.Errorf("position: want 4:9-4:29, got %d:%d-%d:%d", b.Line, b.Column, b.EndLine, b.EndColumn)
+	}
+	if b.Function != "search" {
+		t.Errorf("function: want search, got %q", b.Function)
+	}
+	want := "func (s *Server) search(w http.ResponseWriter, r *http.Request) {\n" +
+		"\tq := r.URL.Query().Get(\"q\")\n" +
+		"\t_ = q\n" +
+		"}"
+	if b.SnippetStart != 2 || b.Snippet != "\n"+want {
+		t.Errorf("snippet: want start 2 and %q, got %d and %q", "\n"+want, b.SnippetStart, b.Snippet)
+	}
+}
+
+func TestEnclosingRouteHandler(t *testing.T) {
+	code := "router.post('/users/:id', async (req, res) => {\n" +
+		"  if (req.body.name) {\n" +
+		"    save(req.params.id)\n" +
+		"  }\n" +
+		"})\n"
+	bs := ScanContent(code, "routes.js", ".js")
+	if len(bs) != 2 {
+		t.Fatalf("want 2 boundaries, got %d", len(bs))
+	}
+	for _, b := range bs {
+		if b.Function != "POST /users/:id" {
+			t.Errorf("%s: function = %q, want %q", b.Variable, b.Function, "POST /users/:id")
+		}
+	}
+}
+
+func TestEnclosingFunctionIndentScoped(t *testing.T) {
+	code := "def first():\n" +
+		"    pass\n\n" +
+		"def view():\n" +
+		"    if True:\n" +
+		"        q = request.args.get('q')\n\n" +
+		"KEY = os.getenv('KEY')\n"
+	bs := ScanContent(code, "views.py", ".py")
+	if len(bs) != 2 {
+		t.Fatalf("want 2 boundaries, got %d", len(bs))
+	}
+	if bs[0].Function != "view" {
+		t.Errorf("want view, got %q", bs[0].Function)
+	}
+	if bs[1].Function != "" {
+		t.Errorf("module-level read should have no function, got %q", bs[1].Function)
+	}
+	if bs[1].SnippetStart != 6 || bs[1].Snippet != "        q = request.args.get('q')\n\nKEY = os.getenv('KEY')" {
+		t.Errorf("unexpected snippet %q", bs[1].Snippet)
+	}
+}
+
+func TestFormatSuggestedByName(t *testing.T) {
+	bs := ScanContent("app.get('/u', (req, res) => {\n  const id = req.query.user_id\n})\n", "app.js", ".js")
+	if len(bs) != 1 {
+		t.Fatalf("want 1 boundary, got %+v", bs)
+	}
+	if v := bs[0].Validation; v[len(v)-1] != "validate as uuid if it always is one (suggested by its name)" {
+		t.Errorf("want a uuid suggestion, got %q", v)
+	}
+	if es := EntriesFromBoundaries(bs); entryFormat(es[0]) != "" {
+		t.Errorf("a suggested format must not become a rule: %+v", es[0])
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+var csExts = []string{".cs"}
+
+var csRules = []rule{
+	{"http_query", "ASP.NET Core",
+		regexp.MustCompile(`Request\.Query\[\s*"([^"]+)"\s*\]`), 1},
+	{"http_header", "ASP.NET Core",
+		regexp.MustCompile(`Request\.Headers\[\s*"([^"]+)"\s*\]`), 1},
+	{"env_var", "Env Var",
+		regexp.MustCompile(`Environment\.GetEnvironmentVariable\(\s*"([^"]+)"`), 1},
+}
+
+func init() {
+	RegisterDetector("C#", &regexDetector{exts: csExts, rules: csRules, validate: genDotnetValidation})
+	RegisterDetector("C#", &funcDetector{exts: csExts, detect: scanCSharp, version: 1})
+	registerFuncDecl(csExts, regexp.MustCompile(
+		`^\s*(?:(?:public|private|protected|internal|static|async|virtual|override|sealed)\s+)+[\w<>\[\],.? ]+?\s+(?P<name>\w+)\s*\(`+
+			`|\.Map(?P<method>Get|Post|Put|Patch|Delete)\(\s*"(?P<route>[^"]*)"`), false)
+}
+
+var (
+	csFromAttrRe = regexp.MustCompile(
+		`\[From(Query|Route|Body|Header|Form)(?:\s*\(\s*Name\s*=\s*"([^"]+)"\s*\))?\]\s*([\w.]+(?:<[^>]*>)?(?:\[\])?\??)\s+(\w+)`)
+	csMapRe = regexp.MustCompile(`\.Map(?:Get|Post|Put|Delete|Patch)\(\s*"([^"]*)"\s*,\s*(?:async\s+)?\(`)
+)
+
+var csAttrTypes = map[string]string{
+	"Query":  "http_query",
+	"Route":  "http_path",
+	"Body":   "http_body",
+	"Header": "http_header",
+	"Form":   "http_body",
+}
+
+// csSimpleTypes are bound from the route or query string by minimal APIs;
+// any other parameter type is deserialized from the request body.
+var csSimpleTypes = map[string]bool{
+	"string": true, "int": true, "long": true, "short": true, "uint": true,
+	"ulong": true, "byte": true, "bool": true, "float": true, "double": true,
+	"decimal": true, "Guid": true, "DateTime": true, "DateTimeOffset": true,
+	"DateOnly": true, "TimeOnly": true,
+}
+
+// csInjectedTypes are supplied by the framework rather than the caller.
+var csInjectedTypes = map[string]bool{
+	"HttpContext": true, "HttpRequest": true, "HttpResponse": true,
+	"CancellationToken": true, "ClaimsPrincipal": true,
+}
+
+// scanCSharp finds ASP.NET Core [FromX] parameters and minimal API lambda
+// parameters.
+func scanCSharp(content, path string) []Boundary {
+	idx := newLineIndex(content)
+	var out []Boundary
+	for _, m := range csFromAttrRe.FindAllStringSubmatchIndex(content, -1) {
+		typ := csAttrTypes[content[m[2]:m[3]]]
+		name := content[m[8]:m[9]]
+		if m[4] >= 0 {
+			name = content[m[4]:m[5]]
+		}
+		b := csBoundary(path, typ, name)
+		idx.span(&b, m[0], m[1])
+		out = append(out, b)
+	}
+
+	for _, m := range csMapRe.FindAllStringSubmatchIndex(content, -1) {
+		route := content[m[2]:m[3]]
+		params, ok := csLambdaParams(content[m[1]:])
+		if !ok {
+			continue
+		}
+		for _, p := range params {
+			if strings.Contains(p.text, "[") {
+				// Attributed parameters are reported by csFromAttrRe;
+				// [FromServices] and friends are not caller input.
+				continue
+			}
+			fields := strings.Fields(p.text)
+			if len(fields) < 2 {
+				continue
+			}
+			ptype := strings.TrimSuffix(fields[len(fields)-2], "?")
+			name := fields[len(fields)-1]
+			if csInjectedTypes[ptype] || isInterfaceName(ptype) {
+				continue
+			}
+			typ := "http_body"
+			switch {
+			case strings.Contains(route, "{"+name+"}") || strings.Contains(route, "{"+name+":"):
+				typ = "http_path"
+			case csSimpleTypes[strings.TrimSuffix(ptype, "[]")]:
+				typ = "http_query"
+			}
+			b := csBoundary(path, typ, name)
+			b.Confidence = "low"
+			idx.span(&b, m[1]+p.off, m[1]+p.off+len(p.text))
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+func csBoundary(path, typ, name string) Boundary {
+	return Boundary{
+		File: path, Type: typ,
+		Source: "ASP.NET Core", Variable: name,
+		Validation: genDotnetValidation(typ),
+		FuzzInputs: genFuzz(typ),
+	}
+}
+
+// csParam is a lambda parameter and its byte offset in the parameter list.
+type csParam struct {
+	text string
+	off  int
+}
+
+// csLambdaParams splits the parameter list that starts right after an opening
+// parenthesis, and reports whether it is followed by a lambda arrow.
+func csLambdaParams(s string) ([]csParam, bool) {
+	depth, start := 0, 0
+	var params []csParam
+	add := func(end int) {
+		raw := s[start:end]
+		if text := strings.TrimSpace(raw); text != "" {
+			params = append(params, csParam{text, start + len(raw) - len(strings.TrimLeft(raw, " \t\r\n"))})
+		}
+	}
+	for i, r := range s {
+		switch r {
+		case '(', '[', '<':
+			depth++
+		case ']', '>':
+			depth--
+		case ',':
+			if depth == 0 {
+				add(i)
+				start = i + 1
+			}
+		case ')':
+			if depth > 0 {
+				depth--
+				continue
+			}
+			add(i)
+			return params, strings.HasPrefix(strings.TrimSpace(s[i+1:]), "=>")
+		}
+	}
+	return nil, false
+}
+
+// isInterfaceName reports whether name follows the .NET IFoo interface
+// convention, which minimal APIs resolve from dependency injection.
+func isInterfaceName(name string) bool {
+	return len(name) > 1 && name[0] == 'I' && name[1] >= 'A' && name[1] <= 'Z'
+}
+
+// genDotnetValidation mirrors genValidation using DataAnnotations attributes.
+func genDotnetValidation(typ string) []string {
+	base := []string{"[Required]", "[StringLength(1024)]"}
+	switch typ {
+	case "http_query":
+		return append(base, "[RegularExpression] allowlist pattern", "HtmlEncoder.Default.Encode before rendering")
+	case "http_path":
+		return append(base, `[RegularExpression(@"^[\w-]+$")] to reject traversal`, "route constraint such as {id:int}")
+	case "http_body":
+		return append(base, "[ApiController] automatic ModelState validation", "[MaxLength]/[Range] on model properties")
+	case "http_header":
+		return append(base, `[RegularExpression(@"^[^\r\n]*$")] to reject CRLF`, "validate header format")
+	case "env_var":
+		return append(base, "bind to options class with ValidateDataAnnotations()", "ValidateOnStart() to fail fast")
+	}
+	return base
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScanCSharpController(t *testing.T) {
+	code := "[ApiController]\n" +
+		"public class UsersController : ControllerBase {\n" +
+		"    [HttpGet(\"{id}\")]\n" +
+		"    public IActionResult Get([FromRoute] int id, [FromQuery(Name = \"q\")] string? search,\n" +
+		"        [FromHeader(Name = \"X-Tenant\")] string tenant, [FromBody] CreateUser body) {\n" +
+		"        var sort = Request.Query[\"sort\"];\n" +
+		"        var key = Environment.GetEnvironmentVariable(\"API_KEY\");\n" +
+		"    }\n}\n"
+	bs := ScanContent(code, "UsersController.cs", ".cs")
+	if len(bs) != 6 {
+		t.Fatalf("want 6 boundaries, got %d: %+v", len(bs), bs)
+	}
+	assertBoundary(t, bs[0], "id", "http_path", "ASP.NET Core")
+	assertBoundary(t, bs[1], "q", "http_query", "ASP.NET Core")
+	assertBoundary(t, bs[2], "X-Tenant", "http_header", "ASP.NET Core")
+	assertBoundary(t, bs[3], "body", "http_body", "ASP.NET Core")
+	assertBoundary(t, bs[4], "sort", "http_query", "ASP.NET Core")
+	assertBoundary(t, bs[5], "API_KEY", "env_var", "Env Var")
+}
+
+func TestScanCSharpMinimalAPI(t *testing.T) {
+	code := "app.MapGet(\"/items/{id:int}\", async (int id, string? filter, IItemStore store, HttpContext ctx) => {\n" +
+		"    return Results.Ok();\n" +
+		"});\n" +
+		"app.MapPost(\"/items\", (Item item, [FromServices] ILogger log) => Results.Created());\n"
+	bs := ScanContent(code, "Program.cs", ".cs")
+	if len(bs) != 3 {
+		t.Fatalf("want 3 boundaries, got %d: %+v", len(bs), bs)
+	}
+	assertBoundary(t, bs[0], "id", "http_path", "ASP.NET Core")
+	assertBoundary(t, bs[1], "filter", "http_query", "ASP.NET Core")
+	assertBoundary(t, bs[2], "item", "http_body", "ASP.NET Core")
+}
+
+func TestCSharpValidationUsesDataAnnotations(t *testing.T) {
+	bs := ScanContent(`var h = Request.Headers["Referer"];`, "Api.cs", ".cs")
+	if len(bs) != 1 {
+		t.Fatalf("want 1 boundary, got %d", len(bs))
+	}
+	if !strings.Contains(strings.Join(bs[0].Validation, " "), "[RegularExpression") {
+		t.Errorf("want DataAnnotations suggestions, got %v", bs[0].Validation)
+	}
+}
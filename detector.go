@@ -0,0 +1,140 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Detector finds input boundaries in the source files of one language.
+// Implementations register themselves with RegisterDetector from an init
+// function, which is all that is needed for the walker to pick up their
+// extensions.
+type Detector interface {
+	Extensions() []string
+	Detect(content, path string) []Boundary
+}
+
+type registered struct {
+	lang string
+	d    Detector
+}
+
+var detectors []registered
+
+// RegisterDetector adds d to the detectors consulted by ScanContent. lang is
+// the human-readable language name used in reports.
+func RegisterDetector(lang string, d Detector) {
+	detectors = append(detectors, registered{lang, d})
+}
+
+// detectorsFor returns the detectors that handle extension ext, in
+// registration order.
+func detectorsFor(ext string) []Detector {
+	var out []Detector
+	for _, r := range detectors {
+		if hasExt(r.d.Extensions(), ext) {
+			out = append(out, r.d)
+		}
+	}
+	return out
+}
+
+// Scannable reports whether files with extension ext are scanned.
+func Scannable(ext string) bool {
+	return len(detectorsFor(ext)) > 0
+}
+
+// Language returns the name of the language registered for ext, or "" if no
+// detector handles it.
+func Language(ext string) string {
+	for _, r := range detectors {
+		if hasExt(r.d.Extensions(), ext) {
+			return r.lang
+		}
+	}
+	return ""
+}
+
+func hasExt(exts []string, ext string) bool {
+	for _, e := range exts {
+		if e == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// rule matches a single input source on one line of code.
+type rule struct {
+	typ    string
+	source string
+	re     *regexp.Regexp
+	idx    int
+}
+
+// regexDetector is the regex engine. Each of rules is tried against every
+// line of the file and reports every match on it; multiline rules run once
+// over the whole file so that calls wrapped across lines still match.
+type regexDetector struct {
+	exts      []string
+	rules     []rule
+	multiline []rule
+	// validate returns validation suggestions for a boundary type; nil means
+	// genValidation.
+	validate func(typ string) []string
+}
+
+func (d *regexDetector) Extensions() []string { return d.exts }
+
+func (d *regexDetector) Detect(content, path string) []Boundary {
+	validate := d.validate
+	if validate == nil {
+		validate = genValidation
+	}
+	var out []Boundary
+	idx := newLineIndex(content)
+	add := func(r rule, m []int, base int) {
+		if 2*r.idx+1 >= len(m) || m[2*r.idx] < 0 {
+			return
+		}
+		b := Boundary{
+			File: path, Type: r.typ,
+			Source: r.source, Variable: content[base+m[2*r.idx] : base+m[2*r.idx+1]],
+			Validation: validate(r.typ),
+			FuzzInputs: genFuzz(r.typ),
+		}
+		idx.span(&b, base+m[0], base+m[1])
+		out = append(out, b)
+	}
+	for i, line := range strings.Split(content, "\n") {
+		for _, r := range d.rules {
+			for _, m := range r.re.FindAllStringSubmatchIndex(line, -1) {
+				add(r, m, idx[i])
+			}
+		}
+	}
+	for _, r := range d.multiline {
+		for _, m := range r.re.FindAllStringSubmatchIndex(content, -1) {
+			add(r, m, 0)
+		}
+	}
+	return out
+}
+
+// funcDetector adapts a whole-file scan function to the Detector interface,
+// for constructs that a single-line regex cannot express.
+type funcDetector struct {
+	exts   []string
+	detect func(content, path string) []Boundary
+	// version identifies detect's rules in RuleSetHash. Bump it whenever a
+	// change to detect alters what it reports.
+	version int
+}
+
+func (d *funcDetector) Extensions() []string { return d.exts }
+
+func (d *funcDetector) RulesVersion() int { return d.version }
+
+func (d *funcDetector) Detect(content, path string) []Boundary {
+	return d.detect(content, path)
+}
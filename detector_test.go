@@ -0,0 +1,42 @@
+package main
+
+import "testing"
+
+type stubDetector struct{}
+
+func (stubDetector) Extensions() []string { return []string{".zz"} }
+
+func (stubDetector) Detect(content, path string) []Boundary {
+	return []Boundary{{File: path, Line: 1, Type: "env_var", Source: "Stub", Variable: content}}
+}
+
+func TestRegisterDetector(t *testing.T) {
+	saved := detectors
+	t.Cleanup(func() { detectors = saved })
+
+	if Scannable(".zz") {
+		t.Fatal(".zz should not be scannable before registration")
+	}
+	RegisterDetector("Zed", stubDetector{})
+	if !Scannable(".zz") {
+		t.Fatal(".zz should be scannable after registration")
+	}
+	if got := Language(".zz"); got != "Zed" {
+		t.Errorf("Language(.zz) = %q, want Zed", got)
+	}
+	bs := ScanContent("TOKEN", "x.zz", ".zz")
+	if len(bs) != 1 || bs[0].Variable != "TOKEN" {
+		t.Fatalf("want stub boundary, got %+v", bs)
+	}
+}
+
+func TestLanguageNames(t *testing.T) {
+	for ext, want := range map[string]string{
+		".go": "Go", ".py": "Python", ".ts": "JavaScript", ".rs": "Rust",
+		".cs": "C#", ".php": "PHP", ".rake": "Ruby", ".txt": "",
+	} {
+		if got := Language(ext); got != want {
+			t.Errorf("Language(%s) = %q, want %q", ext, got, want)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestInferFormat(t *testing.T) {
+	for name, want := range map[string]string{
+		"email":         "email",
+		"user_email":    "email",
+		"id":            "",
+		"redirect_url":  "url",
+		"callbackURL":   "url",
+		"ip_address":    "ip",
+		"token":         "",
+		"sha256":        "hex",
+		"page":          "",
+		"date_of_birth": "",
+		// Only suggested: ids may be numeric, durations "30s".
+		"userId":  "",
+		"timeout": "",
+	} {
+		if got := InferFormat(name); got != want {
+			t.Errorf("InferFormat(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestSuggestFormat(t *testing.T) {
+	for name, want := range map[string]string{
+		"userId":       "uuid",
+		"X-Request-Id": "uuid",
+		"createdAt":    "date-time",
+		"access_token": "jwt",
+		"api_version":  "semver",
+		"phone":        "phone",
+		"ttl":          "duration",
+		"callback":     "url",
+		"email":        "",
+		"page":         "",
+	} {
+		if got := SuggestFormat(name); got != want {
+			t.Errorf("SuggestFormat(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestNameTokens(t *testing.T) {
+	for in, want := range map[string]string{
+		"redirectURL":  "redirect url",
+		"HTTPServer":   "http server",
+		"X-Request-Id": "x request id",
+		"user_id2":     "user id2",
+	} {
+		if got := strings.Join(nameTokens(in), " "); got != want {
+			t.Errorf("nameTokens(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestEntryFormat(t *testing.T) {
+	cases := []struct {
+		e    BoundaryEntry
+		want string
+	}{
+		{BoundaryEntry{ParamName: "email"}, "email"},
+		{BoundaryEntry{ParamName: "q", Format: "slug"}, "slug"},
+		{BoundaryEntry{ParamName: "email", Format: "none"}, ""},
+		{BoundaryEntry{ParamName: "DATABASE_URL", Source: "env_var"}, ""},
+		{BoundaryEntry{ParamName: "user_id", DataType: "int"}, ""},
+	}
+	for _, c := range cases {
+		if got := entryFormat(c.e); got != c.want {
+			t.Errorf("entryFormat(%+v) = %q, want %q", c.e, got, c.want)
+		}
+	}
+}
+
+func TestFormatPatternsCompile(t *testing.T) {
+	for _, f := range FormatNames() {
+		p := formatPattern(f, defaultSchemes)
+		if p == "" {
+			t.Errorf("%s: no portable pattern", f)
+			continue
+		}
+		if _, err := regexp.Compile(p); err != nil {
+			t.Errorf("%s: %v", f, err)
+		}
+		if len(FormatSeeds(f)) < 4 {
+			t.Errorf("%s: want at least 4 fuzz seeds", f)
+		}
+	}
+	re := regexp.MustCompile(formatPattern("url", []string{"https"}))
+	if !re.MatchString("https://example.com/a?b") || re.MatchString("http://example.com") || re.MatchString("https://u@evil.example") {
+		t.Error("url pattern should follow the scheme allowlist and reject userinfo")
+	}
+}
+
+func TestGenerateValidatorsFormats(t *testing.T) {
+	var entries []BoundaryEntry
+	for _, f := range FormatNames() {
+		entries = append(entries, BoundaryEntry{ParamName: "v_" + f, DataType: "string", Format: f, Handler: "h"})
+	}
+	entries = append(entries, BoundaryEntry{ParamName: "next", Format: "url", Schemes: []string{"https"}})
+	src, err := GenerateValidators("validate", entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	typeCheck(t, src)
+	for _, want := range []string{
+		`"net/mail"`, `"net/netip"`, `"net/url"`, `"encoding/base64"`, `"encoding/hex"`, `"time"`,
+		"if a, err := mail.ParseAddress(v); err != nil || a.Address != v {",
+		`if u, err := url.Parse(v); err != nil || u.Host == "" || u.User != nil || !(u.Scheme == "https") {`,
+		"if !formatUuid.MatchString(v) {",
+		`return &ValidationError{Field: "v_email", Rule: "format", Limit: "email", Got: v}`,
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("missing %q", want)
+		}
+	}
+	if _, err := GenerateValidators("validate", []BoundaryEntry{{ParamName: "q", Format: "isbn"}}); err == nil {
+		t.Error("want an error for an unknown format")
+	}
+}
+
+func TestFormatRulesCompile(t *testing.T) {
+	rules, err := GenerateRulesChecked([]BoundaryEntry{
+		{ParamName: "url", DataType: "string", Format: "url"},
+		{ParamName: "mail", DataType: "string", Format: "email"},
+		{ParamName: "type", DataType: "string", MaxLength: 8, Format: "none"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body strings.Builder
+	for _, r := range rules {
+		body.WriteString(r.GoCode + "\n")
+	}
+	typeCheck(t, "package validate\n\nimport (\n\t\"net/mail\"\n\t\"net/url\"\n)\n\n"+
+		"type ValidationError struct {\n\tField, Rule string\n\tLimit, Got any\n}\n\n"+
+		"func (e *ValidationError) Error() string { return e.Rule }\n\n"+
+		"func check(urlValue, mailValue, typeValue string) error {\n"+body.String()+"return nil\n}\n")
+
+	if _, err := GenerateRulesChecked([]BoundaryEntry{{ParamName: "q", Format: "isbn"}}); err == nil {
+		t.Error("want an error for an unknown format")
+	}
+	if rules := GenerateRules([]BoundaryEntry{{ParamName: "q", DataType: "string", MaxLength: 8, Format: "isbn"}}); len(rules) != 1 || rules[0].RuleType != "length" {
+		t.Errorf("unknown format: want only the length rule, got %+v", rules)
+	}
+}
+
+func TestFormatOutputs(t *testing.T) {
+	e := BoundaryEntry{ParamName: "user_email", DataType: "string", MaxLength: 254}
+	rules := GenerateRules([]BoundaryEntry{e})
+	if r := rules[len(rules)-1]; r.RuleType != "format" || !strings.Contains(r.GoCode, "mail.ParseAddress(user_email)") {
+		t.Errorf("bad format rule: %+v", r)
+	}
+	if s := EntrySchema(e); s.Format != "email" || s.Pattern != "" {
+		t.Errorf("bad schema: %+v", s)
+	}
+	if s := EntrySchema(BoundaryEntry{ParamName: "slug"}); s.Format != "" || s.Pattern == "" {
+		t.Errorf("formats without a JSON Schema name should use a pattern: %+v", s)
+	}
+	if tag := ValidatorTag(e); tag != "max=254,email" {
+		t.Errorf("tag = %q", tag)
+	}
+	fuzz := GenerateFuzzTests([]FuzzEntry{{Name: "redirect_url", Type: "string"}})
+	if !strings.Contains(fuzz, `f.Add("javascript:alert(1)")`) {
+		t.Errorf("missing url seeds:\n%s", fuzz)
+	}
+	if strings.Contains(GenerateFuzzTests([]FuzzEntry{{Name: "REDIRECT_URL", Type: "string", Source: "env_var"}}), "javascript:") {
+		t.Error("env vars should not get inferred format seeds")
+	}
+}
@@ -7,8 +7,8 @@ import (
 	"unicode"
 )
 
-// BoundaryEntry represents a discovered input boundary for fuzz test generation.
-type BoundaryEntry struct {
+// FuzzEntry represents a discovered input boundary for fuzz test generation.
+type FuzzEntry struct {
 	Name       string   // parameter or variable name
 	Type       string   // "string", "int", "uint", "float64"
 	Source     string   // "http_query", "http_header", "env_var"
@@ -16,18 +16,19 @@ type BoundaryEntry struct {
 	Max        int64    // maximum value for numeric types
 	MaxLen     int      // max length for string types
 	FuzzInputs []string // additional seed values
+	Format     string   // semantic format of a string, as in BoundaryEntry.Format
 }
 
 // GenerateFuzzTests produces a complete Go _test.go file containing native fuzz
 // functions for each boundary entry. Seed corpus includes min, max, and off-by-one values.
-func GenerateFuzzTests(entries []BoundaryEntry) string {
+func GenerateFuzzTests(entries []FuzzEntry) string {
 	var buf strings.Builder
 	WriteFuzzTests(&buf, entries)
 	return buf.String()
 }
 
 // WriteFuzzTests writes generated fuzz test source code to the provided writer.
-func WriteFuzzTests(w io.Writer, entries []BoundaryEntry) {
+func WriteFuzzTests(w io.Writer, entries []FuzzEntry) {
 	fmt.Fprintln(w, "package boundaryguard_test")
 	fmt.Fprintln(w)
 	fmt.Fprintln(w, "import (")
@@ -42,8 +43,14 @@ func WriteFuzzTests(w io.Writer, entries []BoundaryEntry) {
 
 // fuzzFuncName converts a boundary name like "user_name" into "FuzzUserName".
 func fuzzFuncName(name string) string {
+	return "Fuzz" + camelName(name)
+}
+
+// camelName converts a name like "user_name" or "X-Request-Id" into
+// "UserName" or "XRequestId", dropping any character not valid in a Go
+// identifier.
+func camelName(name string) string {
 	var b strings.Builder
-	b.WriteString("Fuzz")
 	upper := true
 	for _, r := range name {
 		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
@@ -60,7 +67,7 @@ func fuzzFuncName(name string) string {
 	return b.String()
 }
 
-func writeFuzzFunc(w io.Writer, e BoundaryEntry) {
+func writeFuzzFunc(w io.Writer, e FuzzEntry) {
 	name := fuzzFuncName(e.Name)
 	switch e.Type {
 	case "int":
@@ -74,7 +81,7 @@ func writeFuzzFunc(w io.Writer, e BoundaryEntry) {
 	}
 }
 
-func writeStringFuzz(w io.Writer, name string, e BoundaryEntry) {
+func writeStringFuzz(w io.Writer, name string, e FuzzEntry) {
 	fmt.Fprintf(w, "func %s(f *testing.F) {\n", name)
 	fmt.Fprintf(w, "\tf.Add(\"\")\n")
 	fmt.Fprintf(w, "\tf.Add(\"a\")\n")
@@ -88,13 +95,20 @@ func writeStringFuzz(w io.Writer, name string, e BoundaryEntry) {
 	for _, fi := range e.FuzzInputs {
 		fmt.Fprintf(w, "\tf.Add(%q)\n", fi)
 	}
+	format := entryFormat(BoundaryEntry{ParamName: e.Name, DataType: e.Type, Source: e.Source, Format: e.Format})
+	for _, seed := range FormatSeeds(format) {
+		fmt.Fprintf(w, "\tf.Add(%q)\n", seed)
+	}
+	for _, seed := range unicodeSeeds {
+		fmt.Fprintf(w, "\tf.Add(%q)\n", seed)
+	}
 	fmt.Fprintf(w, "\tf.Fuzz(func(t *testing.T, v string) {\n")
 	fmt.Fprintf(w, "\t\t_ = v\n")
 	fmt.Fprintf(w, "\t})\n")
 	fmt.Fprintf(w, "}\n")
 }
 
-func writeIntFuzz(w io.Writer, name string, e BoundaryEntry) {
+func writeIntFuzz(w io.Writer, name string, e FuzzEntry) {
 	fmt.Fprintf(w, "func %s(f *testing.F) {\n", name)
 	fmt.Fprintf(w, "\tf.Add(int64(%d))\n", e.Min)
 	fmt.Fprintf(w, "\tf.Add(int64(%d))\n", e.Max)
@@ -107,7 +121,7 @@ func writeIntFuzz(w io.Writer, name string, e BoundaryEntry) {
 	fmt.Fprintf(w, "}\n")
 }
 
-func writeUintFuzz(w io.Writer, name string, e BoundaryEntry) {
+func writeUintFuzz(w io.Writer, name string, e FuzzEntry) {
 	fmt.Fprintf(w, "func %s(f *testing.F) {\n", name)
 	fmt.Fprintf(w, "\tf.Add(uint64(0))\n")
 	if e.Min > 0 {
@@ -122,7 +136,7 @@ func writeUintFuzz(w io.Writer, name string, e BoundaryEntry) {
 	fmt.Fprintf(w, "}\n")
 }
 
-func writeFloatFuzz(w io.Writer, name string, e BoundaryEntry) {
+func writeFloatFuzz(w io.Writer, name string, e FuzzEntry) {
 	minF := float64(e.Min)
 	maxF := float64(e.Max)
 	fmt.Fprintf(w, "func %s(f *testing.F) {\n", name)
@@ -8,7 +8,7 @@ import (
 )
 
 func TestFuzzGenStringBoundary(t *testing.T) {
-	entries := []BoundaryEntry{
+	entries := []FuzzEntry{
 		{Name: "username", Type: "string", Source: "http_query", MaxLen: 255},
 	}
 	out := GenerateFuzzTests(entries)
@@ -40,7 +40,7 @@ func TestFuzzGenStringBoundary(t *testing.T) {
 }
 
 func TestFuzzGenIntBoundary(t *testing.T) {
-	entries := []BoundaryEntry{
+	entries := []FuzzEntry{
 		{Name: "age", Type: "int", Source: "http_query", Min: 0, Max: 150},
 	}
 	out := GenerateFuzzTests(entries)
@@ -69,7 +69,7 @@ func TestFuzzGenIntBoundary(t *testing.T) {
 }
 
 func TestFuzzGenUintBoundary(t *testing.T) {
-	entries := []BoundaryEntry{
+	entries := []FuzzEntry{
 		{Name: "port", Type: "uint", Source: "http_query", Min: 1, Max: 65535},
 	}
 	out := GenerateFuzzTests(entries)
@@ -98,7 +98,7 @@ func TestFuzzGenUintBoundary(t *testing.T) {
 }
 
 func TestFuzzGenFloatBoundary(t *testing.T) {
-	entries := []BoundaryEntry{
+	entries := []FuzzEntry{
 		{Name: "score", Type: "float64", Source: "http_query", Min: 0, Max: 100},
 	}
 	out := GenerateFuzzTests(entries)
@@ -127,7 +127,7 @@ func TestFuzzGenFloatBoundary(t *testing.T) {
 }
 
 func TestFuzzGenMultipleTypes(t *testing.T) {
-	entries := []BoundaryEntry{
+	entries := []FuzzEntry{
 		{Name: "username", Type: "string", Source: "http_query", MaxLen: 100},
 		{Name: "age", Type: "int", Source: "http_query", Min: 0, Max: 200},
 		{Name: "port", Type: "uint", Source: "env_var", Min: 1, Max: 65535},
@@ -156,7 +156,7 @@ func TestFuzzGenMultipleTypes(t *testing.T) {
 
 func TestFuzzGenWriter(t *testing.T) {
 	var buf strings.Builder
-	entries := []BoundaryEntry{
+	entries := []FuzzEntry{
 		{
 			Name:       "token",
 			Type:       "string",
@@ -200,7 +200,7 @@ func TestFuzzGenEmpty(t *testing.T) {
 }
 
 func TestFuzzGenFuncNameSanitization(t *testing.T) {
-	entries := []BoundaryEntry{
+	entries := []FuzzEntry{
 		{Name: "user_name", Type: "string", MaxLen: 50},
 		{Name: "api-key", Type: "string", MaxLen: 128},
 		{Name: "DB.host", Type: "string", MaxLen: 255},
@@ -223,3 +223,18 @@ func TestFuzzGenFuncNameSanitization(t *testing.T) {
 		t.Error("expected dot to be converted to CamelCase")
 	}
 }
+
+func TestFuzzGenUnicodeSeeds(t *testing.T) {
+	out := GenerateFuzzTests([]FuzzEntry{{Name: "name", Type: "string", MaxLen: 8}})
+	for _, want := range []string{
+		`f.Add("\u202egnp.exe")`,
+		`f.Add("\xc0\xaf")`, // overlong "/"
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %s in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(GenerateFuzzTests([]FuzzEntry{{Name: "n", Type: "int"}}), "gnp.exe") {
+		t.Error("Unicode seeds are for strings only")
+	}
+}
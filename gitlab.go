@@ -0,0 +1,213 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// gitlabSASTVersion is the gl-sast-report.json schema version produced.
+const gitlabSASTVersion = "15.0.7"
+
+type gitlabReport struct {
+	Version         string       `json:"version"`
+	Vulnerabilities []gitlabVuln `json:"vulnerabilities"`
+	Scan            gitlabScan   `json:"scan"`
+}
+
+type gitlabVuln struct {
+	ID          string             `json:"id"`
+	Category    string             `json:"category"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Severity    string             `json:"severity"`
+	Solution    string             `json:"solution"`
+	Location    gitlabLocation     `json:"location"`
+	Identifiers []gitlabIdentifier `json:"identifiers"`
+}
+
+type gitlabLocation struct {
+	File      string `json:"file"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+	Method    string `json:"method,omitempty"`
+}
+
+type gitlabIdentifier struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type gitlabTool struct {
+	ID      string       `json:"id"`
+	Name    string       `json:"name"`
+	Version string       `json:"version"`
+	Vendor  gitlabVendor `json:"vendor"`
+}
+
+type gitlabVendor struct {
+	Name string `json:"name"`
+}
+
+type gitlabScan struct {
+	Analyzer  gitlabTool `json:"analyzer"`
+	Scanner   gitlabTool `json:"scanner"`
+	Type      string     `json:"type"`
+	StartTime string     `json:"start_time"`
+	EndTime   string     `json:"end_time"`
+	Status    string     `json:"status"`
+}
+
+// gitlabSeverities maps severity levels to the gl-sast-report.json names.
+var gitlabSeverities = map[string]string{
+	"info": "Info", "low": "Low", "medium": "Medium", "high": "High", "critical": "Critical",
+}
+
+// WriteGitLabSAST renders rpt in GitLab's gl-sast-report.json format so that
+// merge request security widgets can track boundaries across pipelines.
+func WriteGitLabSAST(w io.Writer, rpt Report) error {
+	tool := gitlabTool{ID: "boundaryguard", Name: "BoundaryGuard", Version: version, Vendor: gitlabVendor{"BoundaryGuard"}}
+	out := gitlabReport{
+		Version:         gitlabSASTVersion,
+		Vulnerabilities: []gitlabVuln{},
+		Scan: gitlabScan{
+			Analyzer:  tool,
+			Scanner:   tool,
+			Type:      "sast",
+			StartTime: gitlabTime(rpt.started),
+			EndTime:   gitlabTime(rpt.finished),
+			Status:    "success",
+		},
+	}
+	for _, b := range rpt.Boundaries {
+		sev := gitlabSeverities[b.Severity]
+		if sev == "" {
+			sev = "Unknown"
+		}
+		out.Vulnerabilities = append(out.Vulnerabilities, gitlabVuln{
+			ID:          fingerprintUUID(b.Fingerprint),
+			Category:    "sast",
+			Name:        issueTitle(b),
+			Description: issueDescription(b),
+			Severity:    sev,
+			Solution:    strings.Join(b.Validation, "; "),
+			Location: gitlabLocation{
+				File:      reportPath(b.File),
+				StartLine: b.Line,
+				EndLine:   b.EndLine,
+				Method:    b.Function,
+			},
+			Identifiers: []gitlabIdentifier{{
+				Type:  "boundaryguard_boundary",
+				Name:  "BoundaryGuard " + b.Type,
+				Value: b.Type,
+			}},
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+type codeClimateIssue struct {
+	Type        string              `json:"type"`
+	CheckName   string              `json:"check_name"`
+	Description string              `json:"description"`
+	Content     codeClimateContent  `json:"content"`
+	Categories  []string            `json:"categories"`
+	Location    codeClimateLocation `json:"location"`
+	Severity    string              `json:"severity"`
+	Fingerprint string              `json:"fingerprint"`
+}
+
+type codeClimateContent struct {
+	Body string `json:"body"`
+}
+
+type codeClimateLocation struct {
+	Path  string           `json:"path"`
+	Lines codeClimateLines `json:"lines"`
+}
+
+type codeClimateLines struct {
+	Begin int `json:"begin"`
+	End   int `json:"end"`
+}
+
+// codeClimateSeverities maps severity levels to Code Climate's scale.
+var codeClimateSeverities = map[string]string{
+	"info": "info", "low": "minor", "medium": "major", "high": "critical", "critical": "blocker",
+}
+
+// WriteCodeClimate renders rpt as a Code Climate issue list, the format
+// GitLab's code quality widget reads.
+func WriteCodeClimate(w io.Writer, rpt Report) error {
+	out := []codeClimateIssue{}
+	for _, b := range rpt.Boundaries {
+		sev := codeClimateSeverities[b.Severity]
+		if sev == "" {
+			sev = "info"
+		}
+		out = append(out, codeClimateIssue{
+			Type:        "issue",
+			CheckName:   "boundaryguard/" + b.Type,
+			Description: issueTitle(b),
+			Content:     codeClimateContent{Body: issueDescription(b)},
+			Categories:  []string{"Security"},
+			Location: codeClimateLocation{
+				Path:  reportPath(b.File),
+				Lines: codeClimateLines{Begin: b.Line, End: b.EndLine},
+			},
+			Severity:    sev,
+			Fingerprint: b.Fingerprint,
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+func issueTitle(b Boundary) string {
+	return fmt.Sprintf("Unvalidated %s input %s (%s)", b.Type, b.Variable, b.Source)
+}
+
+func issueDescription(b Boundary) string {
+	var s strings.Builder
+	fmt.Fprintf(&s, "%s reads %s input %q", b.Source, b.Type, b.Variable)
+	if b.Function != "" {
+		fmt.Fprintf(&s, " in %s", b.Function)
+	}
+	if b.Sink != "" {
+		fmt.Fprintf(&s, " and passes it to a %s sink", b.Sink)
+	}
+	if b.Guarded {
+		s.WriteString("; some validation was found")
+	}
+	fmt.Fprintf(&s, ". Suggested rules: %s.", strings.Join(b.Validation, "; "))
+	return s.String()
+}
+
+// reportPath returns path as a clean, slash-separated path, which is what
+// GitLab matches against repository files.
+func reportPath(path string) string {
+	return filepath.ToSlash(filepath.Clean(path))
+}
+
+// fingerprintUUID formats the first 16 bytes of a hex fingerprint as a UUID.
+func fingerprintUUID(fp string) string {
+	if len(fp) < 32 {
+		return fp
+	}
+	return fp[0:8] + "-" + fp[8:12] + "-" + fp[12:16] + "-" + fp[16:20] + "-" + fp[20:32]
+}
+
+func gitlabTime(t time.Time) string {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	return t.UTC().Format("2006-01-02T15:04:05")
+}
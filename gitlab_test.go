@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const gitlabSrc = `package main
+
+func h(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	id := r.Header.Get("X-Id")
+}
+`
+
+func TestFingerprintStableAcrossLineShifts(t *testing.T) {
+	a := ScanContent(gitlabSrc, "api.go", ".go")
+	b := ScanContent(strings.Replace(gitlabSrc, "package main\n", "package main\n\n// moved\n\n", 1), "./api.go", ".go")
+	if len(a) != 2 || len(b) != 2 {
+		t.Fatalf("want 2 boundaries each, got %d and %d", len(a), len(b))
+	}
+	for i := range a {
+		if a[i].Line == b[i].Line {
+			t.Fatalf("lines should have shifted: %d", a[i].Line)
+		}
+		if a[i].Fingerprint == "" || a[i].Fingerprint != b[i].Fingerprint {
+			t.Errorf("fingerprint changed for %s: %q vs %q", a[i].Variable, a[i].Fingerprint, b[i].Fingerprint)
+		}
+	}
+	if a[0].Fingerprint == a[1].Fingerprint {
+		t.Error("distinct boundaries share a fingerprint")
+	}
+}
+
+func TestFingerprintDuplicates(t *testing.T) {
+	src := "package main\nfunc h() {\n\ta := os.Getenv(\"PORT\")\n\tb := os.Getenv(\"PORT\")\n}\n"
+	bs := ScanContent(src, "cfg.go", ".go")
+	if len(bs) != 2 || bs[0].Fingerprint == bs[1].Fingerprint {
+		t.Fatalf("repeated reads should get distinct fingerprints: %+v", bs)
+	}
+}
+
+func TestWriteGitLabSAST(t *testing.T) {
+	rpt := Report{Boundaries: ScanContent(gitlabSrc, "api.go", ".go")}
+	var buf strings.Builder
+	if err := WriteGitLabSAST(&buf, rpt); err != nil {
+		t.Fatal(err)
+	}
+	var got gitlabReport
+	if err := json.Unmarshal([]byte(buf.String()), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if got.Version != gitlabSASTVersion || got.Scan.Type != "sast" || got.Scan.Scanner.ID != "boundaryguard" {
+		t.Errorf("bad header: %+v", got)
+	}
+	if len(got.Vulnerabilities) != 2 {
+		t.Fatalf("want 2 vulnerabilities, got %d", len(got.Vulnerabilities))
+	}
+	v := got.Vulnerabilities[0]
+	if v.Location.File != "api.go" || v.Location.StartLine != 4 || v.Location.Method != "h" {
+		t.Errorf("bad location: %+v", v.Location)
+	}
+	if len(v.ID) != 36 || v.Severity != gitlabSeverities[rpt.Boundaries[0].Severity] {
+		t.Errorf("bad id or severity: %q %q", v.ID, v.Severity)
+	}
+}
+
+func TestWriteCodeClimate(t *testing.T) {
+	rpt := Report{Boundaries: []Boundary{
+		{File: "./a.go", Line: 3, EndLine: 3, Type: "env_var", Severity: "low", Fingerprint: "abc"},
+		{File: "a.go", Line: 5, EndLine: 5, Type: "http_query", Severity: "critical", Fingerprint: "def"},
+	}}
+	var buf strings.Builder
+	if err := WriteCodeClimate(&buf, rpt); err != nil {
+		t.Fatal(err)
+	}
+	var got []codeClimateIssue
+	if err := json.Unmarshal([]byte(buf.String()), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("want 2 issues, got %d", len(got))
+	}
+	if got[0].Severity != "minor" || got[1].Severity != "blocker" {
+		t.Errorf("bad severity mapping: %q %q", got[0].Severity, got[1].Severity)
+	}
+	if got[0].Location.Path != "a.go" || got[0].CheckName != "boundaryguard/env_var" || got[0].Fingerprint != "abc" {
+		t.Errorf("bad issue: %+v", got[0])
+	}
+}
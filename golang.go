@@ -0,0 +1,554 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var goExts = []string{".go"}
+
+// goRules run in multiline mode: gofmt happily wraps long call chains and
+// argument lists, so whitespace (including newlines) is allowed around dots
+// and parentheses.
+var goRules = []rule{
+	{"http_query", "URL Query",
+		regexp.MustCompile(`(?:URL\.Query\(\)\s*\.\s*Get|FormValue)\(\s*"([^"]+)"\s*,?\s*\)`), 1},
+	{"env_var", "Env Var",
+		regexp.MustCompile(`os\.(?:Getenv|LookupEnv)\(\s*"([^"]+)"\s*,?\s*\)`), 1},
+	{"http_header", "HTTP Header",
+		regexp.MustCompile(`Header\s*\.\s*Get\(\s*"([^"]+)"\s*,?\s*\)`), 1},
+	{"env_var", "Viper",
+		regexp.MustCompile(`viper\.Get\w*\(\s*"([^"]+)"`), 1},
+}
+
+func init() {
+	RegisterDetector("Go", &regexDetector{exts: goExts, multiline: goRules})
+	RegisterDetector("Go", &goConfigDetector{})
+	RegisterDetector("Go", &goBindDetector{})
+	registerFuncDecl(goExts, regexp.MustCompile(
+		`^\s*func\s+(?:\([^)]*\)\s*)?(?P<name>\w+)|\b(?:Handle(?:Func)?|(?P<method>Get|Post|Put|Patch|Delete))\(\s*"(?P<route>/[^"]*)"`), false)
+}
+
+// goConfigDetector parses Go source and reports each struct field tagged for
+// a struct-tag-driven config loader as its own env_var boundary, carrying the
+// field's declared type. Structs passed to envconfig.Process in the same file
+// report every exported field under the variable name envconfig reads,
+// prefix included.
+type goConfigDetector struct{}
+
+// goConfigTags maps a struct tag key to the loader that reads it.
+var goConfigTags = []struct {
+	key, source string
+	upper       bool
+}{
+	{"envconfig", "envconfig", true},
+	{"env", "caarlos0/env", false},
+}
+
+func (*goConfigDetector) Extensions() []string { return goExts }
+
+// RulesVersion identifies the loader rules in RuleSetHash. Bump it whenever a
+// change here alters what Detect reports.
+func (*goConfigDetector) RulesVersion() int { return 1 }
+
+func (*goConfigDetector) Detect(content, path string) []Boundary {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, content, parser.SkipObjectResolution)
+	if err != nil {
+		return nil
+	}
+	structs := fileStructs(f)
+
+	ec := &envconfigSpec{fset: fset, path: path, structs: structs, done: map[*ast.StructType]bool{}}
+	for _, call := range envconfigCalls(f) {
+		st := structs[call.typ]
+		if st == nil {
+			// The spec type lives in another file: report the call itself.
+			name := "*"
+			if call.prefix != "" {
+				name = strings.ToUpper(call.prefix) + "_*"
+			}
+			start, end := fset.Position(call.expr.Pos()), fset.Position(call.expr.End())
+			ec.out = append(ec.out, Boundary{
+				File: path, Line: start.Line, Column: start.Column,
+				EndLine: end.Line, EndColumn: end.Column,
+				Type: "env_var", Source: "envconfig",
+				Variable:   name,
+				Confidence: "low",
+				Validation: genValidation("env_var"),
+				FuzzInputs: genFuzz("env_var"),
+			})
+			continue
+		}
+		if !ec.done[st] {
+			ec.fields(st, call.prefix)
+		}
+	}
+	out := ec.out
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		st, ok := n.(*ast.StructType)
+		if !ok {
+			return true
+		}
+		for _, field := range st.Fields.List {
+			tag, ok := fieldTag(field)
+			if !ok {
+				continue
+			}
+			for _, ct := range goConfigTags {
+				if ct.key == "envconfig" && ec.done[st] {
+					continue
+				}
+				v, ok := tag.Lookup(ct.key)
+				name, _, _ := strings.Cut(v, ",")
+				if !ok || name == "" || name == "-" {
+					continue
+				}
+				if ct.upper {
+					name = strings.ToUpper(name)
+				}
+				out = append(out, configBoundary(fset, path, field, ct.source, name))
+			}
+		}
+		return true
+	})
+	return out
+}
+
+func fieldTag(field *ast.Field) (reflect.StructTag, bool) {
+	if field.Tag == nil {
+		return "", false
+	}
+	raw, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return "", false
+	}
+	return reflect.StructTag(raw), true
+}
+
+func configBoundary(fset *token.FileSet, path string, field *ast.Field, source, name string) Boundary {
+	start, end := fset.Position(field.Pos()), fset.Position(field.End())
+	return Boundary{
+		File: path, Line: start.Line, Column: start.Column,
+		EndLine: end.Line, EndColumn: end.Column,
+		Type: "env_var", Source: source, Variable: name,
+		Confidence: "high",
+		DataType:   types.ExprString(field.Type),
+		Validation: genValidation("env_var"),
+		FuzzInputs: genFuzz("env_var"),
+	}
+}
+
+// envconfigCall is an envconfig.Process call with its literal prefix and,
+// when it is declared in the file, the name of its spec struct type.
+type envconfigCall struct {
+	expr        *ast.CallExpr
+	prefix, typ string
+}
+
+// envconfigCalls finds envconfig.Process and MustProcess calls in f and
+// resolves the struct type of their spec argument: a composite literal, or a
+// variable or parameter declared in f.
+func envconfigCalls(f *ast.File) []envconfigCall {
+	vars := localTypes(f)
+	var out []envconfigCall
+	ast.Inspect(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) != 2 {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Process" && sel.Sel.Name != "MustProcess" {
+			return true
+		}
+		if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != "envconfig" {
+			return true
+		}
+		c := envconfigCall{expr: call}
+		if lit, ok := call.Args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			c.prefix, _ = strconv.Unquote(lit.Value)
+		}
+		arg := call.Args[1]
+		if u, ok := arg.(*ast.UnaryExpr); ok && u.Op == token.AND {
+			arg = u.X
+		}
+		if id, ok := arg.(*ast.Ident); ok {
+			c.typ = vars[id.Name]
+		} else {
+			c.typ = exprTypeName(arg)
+		}
+		out = append(out, c)
+		return true
+	})
+	return out
+}
+
+// localTypes maps the variables and parameters declared under n to the name
+// of their type, where it is a type declared in this package.
+func localTypes(n ast.Node) map[string]string {
+	vars := map[string]string{}
+	ast.Inspect(n, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.ValueSpec:
+			for i, id := range n.Names {
+				if n.Type != nil {
+					vars[id.Name] = typeName(n.Type)
+				} else if i < len(n.Values) {
+					vars[id.Name] = exprTypeName(n.Values[i])
+				}
+			}
+		case *ast.AssignStmt:
+			if n.Tok != token.DEFINE || len(n.Lhs) != len(n.Rhs) {
+				break
+			}
+			for i, lhs := range n.Lhs {
+				if id, ok := lhs.(*ast.Ident); ok {
+					vars[id.Name] = exprTypeName(n.Rhs[i])
+				}
+			}
+		case *ast.FuncType:
+			for _, p := range n.Params.List {
+				for _, id := range p.Names {
+					vars[id.Name] = typeName(p.Type)
+				}
+			}
+		}
+		return true
+	})
+	return vars
+}
+
+// typeName returns the name of a struct type declared in this package, with
+// any pointer stripped, or "".
+func typeName(e ast.Expr) string {
+	if s, ok := e.(*ast.StarExpr); ok {
+		e = s.X
+	}
+	if id, ok := e.(*ast.Ident); ok {
+		return id.Name
+	}
+	return ""
+}
+
+// exprTypeName returns the type of T{}, &T{} or new(T), or "".
+func exprTypeName(e ast.Expr) string {
+	if u, ok := e.(*ast.UnaryExpr); ok && u.Op == token.AND {
+		e = u.X
+	}
+	switch e := e.(type) {
+	case *ast.CompositeLit:
+		return typeName(e.Type)
+	case *ast.CallExpr:
+		if id, ok := e.Fun.(*ast.Ident); ok && id.Name == "new" && len(e.Args) == 1 {
+			return typeName(e.Args[0])
+		}
+	}
+	return ""
+}
+
+// envconfigSpec collects the variables envconfig reads into a spec struct.
+type envconfigSpec struct {
+	fset    *token.FileSet
+	path    string
+	structs map[string]*ast.StructType
+	done    map[*ast.StructType]bool
+	out     []Boundary
+}
+
+// fields reports each exported field of st the way envconfig.Process names
+// it: the envconfig tag, or the field name split at word boundaries when
+// split_words is set, under prefix and upper-cased. Nested structs declared in
+// the file are followed, embedded ones keeping the outer prefix.
+func (s *envconfigSpec) fields(st *ast.StructType, prefix string) {
+	s.done[st] = true
+	for _, field := range st.Fields.List {
+		tag, _ := fieldTag(field)
+		if tag.Get("ignored") == "true" || tag.Get("envconfig") == "-" {
+			continue
+		}
+		nested := s.structs[typeName(field.Type)]
+		if len(field.Names) == 0 {
+			if nested != nil && !s.done[nested] {
+				s.fields(nested, prefix)
+			}
+			continue
+		}
+		for _, id := range field.Names {
+			if !id.IsExported() {
+				continue
+			}
+			key := tag.Get("envconfig")
+			if key == "" {
+				key = id.Name
+				if tag.Get("split_words") == "true" {
+					key = splitWords(key)
+				}
+			}
+			if prefix != "" {
+				key = prefix + "_" + key
+			}
+			key = strings.ToUpper(key)
+			if nested != nil {
+				if !s.done[nested] {
+					s.fields(nested, key)
+				}
+				continue
+			}
+			s.out = append(s.out, configBoundary(s.fset, s.path, field, "envconfig", key))
+		}
+	}
+}
+
+var (
+	envconfigWordRe    = regexp.MustCompile(`([^A-Z]+|[A-Z]+[^A-Z]+|[A-Z]+)`)
+	envconfigAcronymRe = regexp.MustCompile(`([A-Z]+)([A-Z][^A-Z]+)`)
+)
+
+// splitWords joins the words of a CamelCase name with underscores, as
+// envconfig's split_words does: "APIKey" becomes "API_Key".
+func splitWords(name string) string {
+	var words []string
+	for _, w := range envconfigWordRe.FindAllString(name, -1) {
+		if m := envconfigAcronymRe.FindStringSubmatch(w); m != nil {
+			words = append(words, m[1], m[2])
+		} else {
+			words = append(words, w)
+		}
+	}
+	return strings.Join(words, "_")
+}
+
+// goBindDetector reports each field of a struct that a handler decodes the
+// request into as its own boundary, positioned at the decoding call so that
+// the handler is its Function. The struct may be declared in any file of the
+// package.
+type goBindDetector struct{}
+
+// goBindCalls maps the methods that decode a request into a struct to the
+// boundary type they read, the struct tag that names each field and the
+// reported source. Decode only counts on a json.Decoder of a request body.
+var goBindCalls = map[string]struct{ typ, tag, source string }{
+	"Decode":           {"http_body", "json", "JSON Body"},
+	"BindJSON":         {"http_body", "json", "Gin/Echo Bind"},
+	"ShouldBindJSON":   {"http_body", "json", "Gin/Echo Bind"},
+	"Bind":             {"http_body", "json", "Gin/Echo Bind"},
+	"ShouldBind":       {"http_body", "json", "Gin/Echo Bind"},
+	"BindQuery":        {"http_query", "form", "Gin/Echo Bind"},
+	"ShouldBindQuery":  {"http_query", "form", "Gin/Echo Bind"},
+	"BindUri":          {"http_path", "uri", "Gin/Echo Bind"},
+	"ShouldBindUri":    {"http_path", "uri", "Gin/Echo Bind"},
+	"BindHeader":       {"http_header", "header", "Gin/Echo Bind"},
+	"ShouldBindHeader": {"http_header", "header", "Gin/Echo Bind"},
+	"BodyParser":       {"http_body", "json", "Fiber"},
+	"QueryParser":      {"http_query", "query", "Fiber"},
+	"ParamsParser":     {"http_path", "params", "Fiber"},
+	"ReqHeaderParser":  {"http_header", "reqHeader", "Fiber"},
+}
+
+func (*goBindDetector) Extensions() []string { return goExts }
+
+// RulesVersion identifies the binding rules in RuleSetHash. Bump it whenever
+// a change here alters what Detect reports.
+func (*goBindDetector) RulesVersion() int { return 1 }
+
+func (*goBindDetector) Detect(content, path string) []Boundary {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, content, parser.SkipObjectResolution)
+	if err != nil {
+		return nil
+	}
+	var structs map[string]*ast.StructType
+	var out []Boundary
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		vars := localTypes(fn)
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) != 1 {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			bind, ok := goBindCalls[sel.Sel.Name]
+			if !ok || sel.Sel.Name == "Decode" && !isBodyDecoder(sel.X) {
+				return true
+			}
+			arg := call.Args[0]
+			if u, ok := arg.(*ast.UnaryExpr); ok && u.Op == token.AND {
+				arg = u.X
+			}
+			typ := exprTypeName(arg)
+			if id, ok := arg.(*ast.Ident); ok {
+				typ = vars[id.Name]
+			}
+			if typ == "" {
+				return true
+			}
+			if structs == nil {
+				structs = packageStructs(f, path)
+			}
+			if structs[typ] == nil {
+				return true
+			}
+			start, end := fset.Position(call.Pos()), fset.Position(call.End())
+			for _, fld := range structFields(structs, typ, map[string]bool{}) {
+				name, optional := fld.name, false
+				if v, ok := fld.tag.Lookup(bind.tag); ok {
+					v, opts, _ := strings.Cut(v, ",")
+					if v == "-" {
+						continue
+					} else if v != "" {
+						name = v
+					}
+					optional = strings.Contains(","+opts+",", ",omitempty,")
+				}
+				out = append(out, Boundary{
+					File: path, Line: start.Line, Column: start.Column,
+					EndLine: end.Line, EndColumn: end.Column,
+					Type: bind.typ, Source: bind.source, Variable: name,
+					DataType: fld.typ, Field: fld.owner + "." + fld.name,
+					Optional: optional, Confidence: "high",
+					Validation: genValidation(bind.typ),
+					FuzzInputs: genFuzz(bind.typ),
+				})
+			}
+			return true
+		})
+	}
+	return out
+}
+
+// isBodyDecoder reports whether x is json.NewDecoder over a request body,
+// such as json.NewDecoder(r.Body) or json.NewDecoder(http.MaxBytesReader(w,
+// r.Body, n)).
+func isBodyDecoder(x ast.Expr) bool {
+	call, ok := x.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	if sel, ok := call.Fun.(*ast.SelectorExpr); !ok || sel.Sel.Name != "NewDecoder" {
+		return false
+	}
+	body := false
+	ast.Inspect(call, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok && sel.Sel.Name == "Body" {
+			body = true
+		}
+		return !body
+	})
+	return body
+}
+
+// goField is an exported field of a struct, with embedded structs declared in
+// the package flattened into their parent as encoding/json does. owner is the
+// struct that declares the field.
+type goField struct {
+	owner, name, typ string
+	tag              reflect.StructTag
+}
+
+// fileStructs returns the struct types declared at the top level of f.
+func fileStructs(f *ast.File) map[string]*ast.StructType {
+	structs := map[string]*ast.StructType{}
+	for _, d := range f.Decls {
+		gd, ok := d.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			if ts, ok := spec.(*ast.TypeSpec); ok {
+				if st, ok := ts.Type.(*ast.StructType); ok {
+					structs[ts.Name.Name] = st
+				}
+			}
+		}
+	}
+	return structs
+}
+
+// goDirFile holds the structs declared in one non-test Go file on disk.
+type goDirFile struct {
+	path, pkg string
+	structs   map[string]*ast.StructType
+}
+
+var (
+	goDirMu    sync.Mutex
+	goDirCache = map[string][]goDirFile{}
+)
+
+// dirStructs parses the non-test Go files in dir once and returns the
+// structs each declares.
+func dirStructs(dir string) []goDirFile {
+	goDirMu.Lock()
+	defer goDirMu.Unlock()
+	files, ok := goDirCache[dir]
+	if !ok {
+		fset := token.NewFileSet()
+		paths, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+		for _, p := range paths {
+			if strings.HasSuffix(p, "_test.go") {
+				continue
+			}
+			if f, err := parser.ParseFile(fset, p, nil, parser.SkipObjectResolution); err == nil {
+				files = append(files, goDirFile{filepath.Clean(p), f.Name.Name, fileStructs(f)})
+			}
+		}
+		goDirCache[dir] = files
+	}
+	return files
+}
+
+// packageStructs returns the structs declared in f and, when path is on
+// disk, in the other non-test files of its package.
+func packageStructs(f *ast.File, path string) map[string]*ast.StructType {
+	decls := map[string]*ast.StructType{}
+	for _, df := range dirStructs(filepath.Dir(path)) {
+		if df.pkg != f.Name.Name || df.path == filepath.Clean(path) {
+			continue
+		}
+		for name, st := range df.structs {
+			decls[name] = st
+		}
+	}
+	for name, st := range fileStructs(f) {
+		decls[name] = st
+	}
+	return decls
+}
+
+// structFields returns the exported fields of the struct owner in decls,
+// with embedded structs flattened into it.
+func structFields(decls map[string]*ast.StructType, owner string, seen map[string]bool) []goField {
+	seen[owner] = true
+	var fields []goField
+	for _, field := range decls[owner].Fields.List {
+		tag, _ := fieldTag(field)
+		if len(field.Names) == 0 {
+			if inner := typeName(field.Type); decls[inner] != nil && !seen[inner] {
+				fields = append(fields, structFields(decls, inner, seen)...)
+			}
+			continue
+		}
+		for _, id := range field.Names {
+			if id.IsExported() {
+				fields = append(fields, goField{owner, id.Name, types.ExprString(field.Type), tag})
+			}
+		}
+	}
+	return fields
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestScanGoConfigLoaders(t *testing.T) {
+	code := "package config\n\n" +
+		"import \"github.com/spf13/viper\"\n\n" +
+		"func Load() {\n" +
+		"\tport := viper.GetInt(\"server.port\")\n" +
+		"\tif v, ok := os.LookupEnv(\"REGION\"); ok {\n\t\t_ = v\n\t}\n" +
+		"}\n"
+	bs := ScanContent(code, "config.go", ".go")
+	if len(bs) != 2 {
+		t.Fatalf("want 2 boundaries, got %d: %+v", len(bs), bs)
+	}
+	assertBoundary(t, bs[0], "server.port", "env_var", "Viper")
+	assertBoundary(t, bs[1], "REGION", "env_var", "Env Var")
+}
+
+func TestScanGoStructTagLoaders(t *testing.T) {
+	code := "package config\n\n" +
+		"type Spec struct {\n" +
+		"\tPort    int           `envconfig:\"port\" default:\"8080\"`\n" +
+		"\tTimeout time.Duration `env:\"TIMEOUT,required\"`\n" +
+		"\tHosts   []string      `env:\"HOSTS\" envSeparator:\",\"`\n" +
+		"\tinternal bool\n" +
+		"}\n\n" +
+		"func Load() { envconfig.Process(\"app\", &Spec{}) }\n"
+	bs := ScanContent(code, "config.go", ".go")
+	if len(bs) != 5 {
+		t.Fatalf("want 5 boundaries, got %d: %+v", len(bs), bs)
+	}
+	// envconfig also loads the untagged exported fields, under the prefix.
+	assertBoundary(t, bs[0], "APP_PORT", "env_var", "envconfig")
+	assertBoundary(t, bs[1], "APP_TIMEOUT", "env_var", "envconfig")
+	assertBoundary(t, bs[3], "APP_HOSTS", "env_var", "envconfig")
+	bs = []Boundary{bs[0], bs[2], bs[4]}
+	assertBoundary(t, bs[1], "TIMEOUT", "env_var", "caarlos0/env")
+	assertBoundary(t, bs[2], "HOSTS", "env_var", "caarlos0/env")
+	for i, want := range []string{"int", "time.Duration", "[]string"} {
+		if bs[i].DataType != want {
+			t.Errorf("bs[%d].DataType = %q, want %q", i, bs[i].DataType, want)
+		}
+	}
+	if bs[0].Line != 4 {
+		t.Errorf("want field line 4, got %d", bs[0].Line)
+	}
+}
+
+func TestScanGoEnvconfigProcess(t *testing.T) {
+	code := "package config\n\n" +
+		"type DB struct {\n" +
+		"\tMaxConns int `split_words:\"true\"`\n" +
+		"}\n\n" +
+		"type Config struct {\n" +
+		"\tDB\n" +
+		"\tAPIKey string `split_words:\"true\" required:\"true\"`\n" +
+		"\tDebug  bool   `ignored:\"true\"`\n" +
+		"\tCache  Cache\n" +
+		"}\n\n" +
+		"type Cache struct {\n" +
+		"\tTTL time.Duration `envconfig:\"ttl\"`\n" +
+		"}\n\n" +
+		"func Load() (*Config, error) {\n" +
+		"\tvar c Config\n" +
+		"\terr := envconfig.Process(\"svc\", &c)\n" +
+		"\tenvconfig.MustProcess(\"worker\", &other.Spec{})\n" +
+		"\treturn &c, err\n" +
+		"}\n"
+	bs := ScanContent(code, "config.go", ".go")
+	if len(bs) != 4 {
+		t.Fatalf("want 4 boundaries, got %d: %+v", len(bs), bs)
+	}
+	assertBoundary(t, bs[0], "SVC_MAX_CONNS", "env_var", "envconfig")
+	assertBoundary(t, bs[1], "SVC_API_KEY", "env_var", "envconfig")
+	assertBoundary(t, bs[2], "SVC_CACHE_TTL", "env_var", "envconfig")
+	assertBoundary(t, bs[3], "WORKER_*", "env_var", "envconfig")
+	if bs[3].Confidence != "low" {
+		t.Errorf("unresolved spec: want low confidence, got %q", bs[3].Confidence)
+	}
+}
+
+func TestScanGoMultipleMatchesPerLine(t *testing.T) {
+	code := "package main\n" +
+		"func f() { a := os.Getenv(\"A\"); b := os.Getenv(\"B\") }\n"
+	bs := ScanContent(code, "main.go", ".go")
+	if len(bs) != 2 {
+		t.Fatalf("want 2 boundaries, got %d: %+v", len(bs), bs)
+	}
+	assertBoundary(t, bs[0], "A", "env_var", "Env Var")
+	assertBoundary(t, bs[1], "B", "env_var", "Env Var")
+	if bs[0].Column != 17 || bs[1].Column != 38 {
+		t.Errorf("columns: want 17 and 38, got %d and %d", bs[0].Column, bs[1].Column)
+	}
+}
+
+func TestScanGoCallWrappedOverLines(t *testing.T) {
+	code := "package main\n" +
+		"func h(r *http.Request) {\n" +
+		"\tname := r.URL.Query().\n" +
+		"\t\tGet(\"username\")\n" +
+		"\tdb := os.Getenv(\n" +
+		"\t\t\"DB_URL\",\n" +
+		"\t)\n}\n"
+	bs := ScanContent(code, "handler.go", ".go")
+	if len(bs) != 2 {
+		t.Fatalf("want 2 boundaries, got %d: %+v", len(bs), bs)
+	}
+	assertBoundary(t, bs[0], "username", "http_query", "URL Query")
+	assertBoundary(t, bs[1], "DB_URL", "env_var", "Env Var")
+	if bs[0].Line != 3 || bs[0].Column != 12 {
+		t.Errorf("want 3:12, got %d:%d", bs[0].Line, bs[0].Column)
+	}
+	if bs[1].Line != 5 {
+		t.Errorf("want line 5, got %d", bs[1].Line)
+	}
+}
+
+func TestScanGoBoundRequestStruct(t *testing.T) {
+	dir := t.TempDir()
+	mustWrite(t, filepath.Join(dir, "types.go"), "package api\n\n"+
+		"type Page struct {\n\tLimit int `form:\"limit\"`\n}\n\n"+
+		"type CreateUser struct {\n\tName  string `json:\"name\"`\n\tEmail string `json:\"email,omitempty\"`\n"+
+		"\tToken string `json:\"-\"`\n\tnote  string\n}\n")
+	path := filepath.Join(dir, "handler.go")
+	mustWrite(t, path, "package api\n\n"+
+		"func create(w http.ResponseWriter, r *http.Request) {\n"+
+		"\tvar req CreateUser\n"+
+		"\tif err := json.NewDecoder(r.Body).Decode(&req); err != nil {\n\t\treturn\n\t}\n"+
+		"\tjson.NewDecoder(f).Decode(&req)\n"+
+		"}\n\n"+
+		"func list(c *gin.Context) {\n\tc.ShouldBindQuery(&Page{})\n}\n")
+	bs := ScanFile(path, ".go")
+	if len(bs) != 3 {
+		t.Fatalf("want 3 boundaries, got %d: %+v", len(bs), bs)
+	}
+	assertBoundary(t, bs[0], "name", "http_body", "JSON Body")
+	assertBoundary(t, bs[1], "email", "http_body", "JSON Body")
+	assertBoundary(t, bs[2], "limit", "http_query", "Gin/Echo Bind")
+	if bs[1].Field != "CreateUser.Email" || bs[1].DataType != "string" || bs[1].Function != "create" || bs[1].Line != 5 {
+		t.Errorf("bad decoded field: %+v", bs[1])
+	}
+	if bs[2].Field != "Page.Limit" || bs[2].DataType != "int" {
+		t.Errorf("bad bound field: %+v", bs[2])
+	}
+}
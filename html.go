@@ -0,0 +1,230 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// WriteHTML renders rpt as a single self-contained HTML page: summary
+// counts, a sortable table of boundaries, and a source view for every file
+// with the boundary lines highlighted.
+func WriteHTML(w io.Writer, rpt Report) error {
+	return htmlTmpl.Execute(w, buildHTMLReport(rpt))
+}
+
+type htmlReport struct {
+	Report
+	Summaries []htmlSummary
+	Findings  []htmlFinding
+	Files     []htmlFile
+}
+
+type htmlSummary struct {
+	Title  string
+	Counts []htmlCount
+}
+
+type htmlCount struct {
+	Name  string
+	Count int
+}
+
+type htmlFinding struct {
+	Boundary
+	ID       string // anchor of the finding's line in its file view
+	N        int    // position in the report, which keeps ids unique per finding
+	Language string
+	Rank     int
+}
+
+type htmlFile struct {
+	ID       string
+	Path     string
+	Lines    []htmlLine
+	Findings []htmlFinding
+}
+
+type htmlLine struct {
+	N    int
+	Text string
+	Hit  bool
+}
+
+func buildHTMLReport(rpt Report) htmlReport {
+	out := htmlReport{Report: rpt}
+	byFile := map[string]*htmlFile{}
+	var order []string
+	for i, b := range rpt.Boundaries {
+		f, ok := byFile[b.File]
+		if !ok {
+			f = &htmlFile{ID: fmt.Sprintf("file-%d", len(order)+1), Path: b.File}
+			byFile[b.File] = f
+			order = append(order, b.File)
+		}
+		hf := htmlFinding{
+			Boundary: b,
+			ID:       fmt.Sprintf("%s-L%d", f.ID, b.Line),
+			N:        i + 1,
+			Language: fileLanguage(b.File),
+			Rank:     severityRank(b.Severity),
+		}
+		f.Findings = append(f.Findings, hf)
+		out.Findings = append(out.Findings, hf)
+	}
+	for _, path := range order {
+		f := byFile[path]
+		f.Lines = sourceLines(path, f.Findings)
+		out.Files = append(out.Files, *f)
+	}
+	out.Summaries = []htmlSummary{
+		{"Type", sortedCounts(rpt.Boundaries, func(b Boundary) string { return b.Type })},
+		{"Source", sortedCounts(rpt.Boundaries, func(b Boundary) string { return b.Source })},
+		{"Language", sortedCounts(rpt.Boundaries, func(b Boundary) string { return fileLanguage(b.File) })},
+	}
+	return out
+}
+
+// sourceLines returns the lines of path with the boundary lines marked. When
+// the file cannot be read it falls back to the findings' snippets.
+func sourceLines(path string, findings []htmlFinding) []htmlLine {
+	hit := map[int]bool{}
+	for _, f := range findings {
+		for l := f.Line; l <= f.EndLine; l++ {
+			hit[l] = true
+		}
+	}
+	var out []htmlLine
+	if data, err := os.ReadFile(path); err == nil {
+		for i, l := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+			out = append(out, htmlLine{N: i + 1, Text: l, Hit: hit[i+1]})
+		}
+		return out
+	}
+	seen := map[int]bool{}
+	for _, f := range findings {
+		for i, l := range strings.Split(f.Snippet, "\n") {
+			n := f.SnippetStart + i
+			if !seen[n] {
+				seen[n] = true
+				out = append(out, htmlLine{N: n, Text: l, Hit: hit[n]})
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].N < out[j].N })
+	return out
+}
+
+// sortedCounts groups bs by key and returns the groups, largest first.
+func sortedCounts(bs []Boundary, key func(Boundary) string) []htmlCount {
+	counts := map[string]int{}
+	for _, b := range bs {
+		counts[key(b)]++
+	}
+	out := make([]htmlCount, 0, len(counts))
+	for k, v := range counts {
+		out = append(out, htmlCount{k, v})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// fileLanguage returns the registered language name for path's extension.
+func fileLanguage(path string) string {
+	if lang := Language(strings.ToLower(filepath.Ext(path))); lang != "" {
+		return lang
+	}
+	return "unknown"
+}
+
+var htmlTmpl = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>BoundaryGuard Report</title>
+<style>
+body{font-family:system-ui,sans-serif;margin:2rem;color:#1f2328}
+h1{font-size:1.5rem}h2{font-size:1.2rem;margin-top:2rem}
+.summary{display:flex;gap:2rem;flex-wrap:wrap}
+.summary table{min-width:12rem}
+table{border-collapse:collapse;font-size:.9rem}
+th,td{border:1px solid #d0d7de;padding:.3rem .6rem;text-align:left;vertical-align:top}
+th{background:#f6f8fa}
+#findings th{cursor:pointer}
+#findings th:after{content:" \21C5";color:#8c959f}
+.sev-critical{color:#fff;background:#a40e26}.sev-high{color:#fff;background:#cf222e}
+.sev-medium{background:#ffd33d}.sev-low{background:#ddf4ff}.sev-info{background:#f6f8fa}
+.src{font-family:ui-monospace,monospace;font-size:.8rem;border:1px solid #d0d7de;overflow-x:auto}
+.src div{white-space:pre}.src .n{display:inline-block;width:3.5rem;color:#8c959f;text-align:right;padding-right:.8rem;user-select:none}
+.src .hit{background:#fff8c5}
+details{margin:.3rem 0 .8rem}
+code{font-size:.85rem}
+</style>
+</head>
+<body>
+<h1>&#x1f6e1;&#xfe0f; BoundaryGuard Report</h1>
+<p>Files scanned: {{.TotalFiles}} | Boundaries found: {{.TotalBounds}}</p>
+{{if not .Boundaries}}<p>No unguarded boundaries found. Clean!</p>{{else}}
+<div class="summary">
+{{range .Summaries}}<table><thead><tr><th>{{.Title}}</th><th>Count</th></tr></thead><tbody>
+{{range .Counts}}<tr><td>{{.Name}}</td><td>{{.Count}}</td></tr>{{end}}
+</tbody></table>
+{{end}}</div>
+
+<h2>Boundaries</h2>
+<table id="findings">
+<thead><tr><th>Severity</th><th>File</th><th>Line</th><th>Type</th><th>Source</th><th>Variable</th><th>Language</th><th>Function</th><th>Sink</th></tr></thead>
+<tbody>
+{{range .Findings}}<tr>
+<td data-sort="{{.Rank}}" class="sev-{{.Severity}}">{{.Severity}}</td>
+<td><a href="#{{.ID}}">{{.File}}</a></td><td data-sort="{{.Line}}">{{.Line}}</td>
+<td>{{.Type}}</td><td>{{.Source}}</td><td><code>{{.Variable}}</code></td>
+<td>{{.Language}}</td><td>{{.Function}}</td><td>{{.Sink}}</td>
+</tr>
+{{end}}</tbody>
+</table>
+
+<h2>Files</h2>
+{{range .Files}}
+<h3 id="{{.ID}}">{{.Path}}</h3>
+{{range .Findings}}<details id="finding-{{.N}}">
+<summary><span class="sev-{{.Severity}}">{{.Severity}}</span> line {{.Line}}: {{.Type}} <code>{{.Variable}}</code> ({{.Source}}){{if .Guarded}}, guarded{{end}}</summary>
+<p>Validation rules:</p>
+<ul>{{range .Validation}}<li>{{.}}</li>{{end}}</ul>
+<p>Fuzz inputs:</p>
+<ul>{{range .FuzzInputs}}<li><code>{{.}}</code></li>{{end}}</ul>
+</details>
+{{end}}
+<div class="src">{{$id := .ID}}{{range .Lines}}<div{{if .Hit}} class="hit" id="{{$id}}-L{{.N}}"{{end}}><span class="n">{{.N}}</span>{{.Text}}</div>{{end}}</div>
+{{end}}
+{{end}}
+<script>
+document.querySelectorAll("#findings th").forEach(function (th, col) {
+  var asc = true;
+  th.addEventListener("click", function () {
+    var body = th.closest("table").tBodies[0];
+    var rows = Array.prototype.slice.call(body.rows);
+    rows.sort(function (a, b) {
+      var x = a.cells[col].dataset.sort || a.cells[col].textContent;
+      var y = b.cells[col].dataset.sort || b.cells[col].textContent;
+      var nx = Number(x), ny = Number(y);
+      var c = (!isNaN(nx) && !isNaN(ny)) ? nx - ny : x.localeCompare(y);
+      return asc ? c : -c;
+    });
+    asc = !asc;
+    rows.forEach(function (r) { body.appendChild(r); });
+  });
+});
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestWriteHTML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "handler.go")
+	code := "package main\n\nfunc h(w http.ResponseWriter, r *http.Request) {\n" +
+		"\tname := r.URL.Query().Get(\"username\")\n\tfmt.Fprint(w, name)\n}\n"
+	mustWrite(t, path, code)
+	bs := ScanFile(path, ".go")
+	rpt := Report{TotalFiles: 1, TotalBounds: len(bs), Boundaries: bs}
+
+	var buf strings.Builder
+	if err := WriteHTML(&buf, rpt); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		"<th>Language</th>",
+		"<td>Go</td><td>1</td>",
+		`class="sev-high"`,
+		`<div class="hit" id="file-1-L4"><span class="n">4</span>`,
+		"&lt;script&gt;alert(1)&lt;/script&gt;",
+		"escape for the HTML context it is written into",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("HTML report missing %q", want)
+		}
+	}
+	if strings.Contains(out, "<script>alert(1)") {
+		t.Error("fuzz input rendered without escaping")
+	}
+}
+
+func TestWriteHTMLEmpty(t *testing.T) {
+	var buf strings.Builder
+	if err := WriteHTML(&buf, Report{TotalFiles: 3}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "No unguarded boundaries found") {
+		t.Error("empty report should say it is clean")
+	}
+}
+
+func TestWriteHTMLUniqueIDs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "handler.go")
+	mustWrite(t, path, "package main\n\nfunc h(w http.ResponseWriter, r *http.Request) {\n"+
+		"\ta, b := r.URL.Query().Get(\"a\"), r.URL.Query().Get(\"b\")\n}\n")
+	bs := ScanFile(path, ".go")
+	if len(bs) != 2 || bs[0].Line != bs[1].Line {
+		t.Fatalf("want 2 boundaries on one line, got %+v", bs)
+	}
+	var buf strings.Builder
+	if err := WriteHTML(&buf, Report{TotalFiles: 1, TotalBounds: len(bs), Boundaries: bs}); err != nil {
+		t.Fatal(err)
+	}
+	seen := map[string]bool{}
+	for _, m := range regexp.MustCompile(` id="([^"]+)"`).FindAllStringSubmatch(buf.String(), -1) {
+		if seen[m[1]] {
+			t.Errorf("duplicate id %q", m[1])
+		}
+		seen[m[1]] = true
+	}
+	if !seen["finding-1"] || !seen["finding-2"] {
+		t.Errorf("want an id per finding, got %v", seen)
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+var jsExts = []string{".js", ".ts", ".jsx", ".tsx", ".mjs", ".cjs"}
+
+var jsRules = []rule{
+	{"http_query", "Express",
+		regexp.MustCompile(`req\.(query|params|body)\.(\w+)`), 2},
+	{"env_var", "Env Var",
+		regexp.MustCompile(`process\.env\.(\w+)`), 1},
+	{"http_query", "Browser",
+		regexp.MustCompile(`\b((?:window\.|document\.)?location\.(?:search|hash|href))\b`), 1},
+	{"http_cookie", "Browser",
+		regexp.MustCompile(`\b(document\.cookie)\b`), 1},
+	{"post_message", "Browser",
+		regexp.MustCompile(`addEventListener\(\s*['"](message)['"]`), 1},
+	{"post_message", "Browser",
+		regexp.MustCompile(`\b(onmessage)\s*=`), 1},
+}
+
+func init() {
+	RegisterDetector("JavaScript", &regexDetector{exts: jsExts, rules: jsRules})
+	RegisterDetector("JavaScript", &funcDetector{exts: jsExts, detect: scanSearchParams, version: 2})
+	RegisterDetector("JavaScript", &funcDetector{exts: jsExts, detect: scanNodeConfig, version: 2})
+	RegisterDetector("Vue", &sfcDetector{exts: []string{".vue"}})
+	RegisterDetector("Svelte", &sfcDetector{exts: []string{".svelte"}})
+	jsDecl := regexp.MustCompile(`\bfunction\s*\*?\s*(?P<name>\w+)` +
+		`|(?:const|let|var)\s+(?P<name>\w+)\s*=\s*(?:async\s+)?(?:function\b|\([^)]*\)\s*=>|\w+\s*=>)` +
+		`|\b\w+\.(?P<method>get|post|put|patch|delete|all|use)\(\s*['"](?P<route>/[^'"]*)['"]` +
+		`|^\s*(?:(?:public|private|protected|static|async)\s+)*(?P<name>\w+)\s*\([^)]*\)\s*(?::\s*[^{]+)?\{`)
+	registerFuncDecl(jsExts, jsDecl, false)
+	registerFuncDecl([]string{".vue", ".svelte"}, jsDecl, false)
+}
+
+var (
+	searchParamsBindRe = regexp.MustCompile(
+		`(?:const|let|var)\s+(?:(\w+)|\[\s*(\w+)[^\]]*\])\s*=\s*(?:new\s+URLSearchParams\(|useSearchParams\(|[\w.]+\.searchParams\b)`)
+	searchParamsInlineRe = regexp.MustCompile(
+		`(?:new\s+URLSearchParams\([^)]*\)|\.searchParams)\.get(?:All)?\(\s*['"]([^'"]+)['"]`)
+	// searchParamsGetRe matches a get on any receiver; scanSearchParams keeps
+	// those whose receiver is a bound variable.
+	searchParamsGetRe = regexp.MustCompile(`\b(\w+)\.get(?:All)?\(\s*['"]([^'"]+)['"]`)
+)
+
+// scanSearchParams reports each parameter read through URLSearchParams, either
+// inline or through a variable bound to a URLSearchParams instance.
+func scanSearchParams(content, path string) []Boundary {
+	idx := newLineIndex(content)
+	var out []Boundary
+	add := func(m []int) {
+		b := Boundary{
+			File: path, Type: "http_query",
+			Source: "Browser", Variable: content[m[2]:m[3]],
+			Validation: genValidation("http_query"),
+			FuzzInputs: genFuzz("http_query"),
+		}
+		idx.span(&b, m[0], m[1])
+		out = append(out, b)
+	}
+	for _, m := range searchParamsInlineRe.FindAllStringSubmatchIndex(content, -1) {
+		add(m)
+	}
+	bound := map[string]bool{}
+	for _, b := range searchParamsBindRe.FindAllStringSubmatch(content, -1) {
+		bound[b[1]+b[2]] = true
+	}
+	if len(bound) == 0 {
+		return out
+	}
+	for _, m := range searchParamsGetRe.FindAllStringSubmatchIndex(content, -1) {
+		if bound[content[m[2]:m[3]]] {
+			add([]int{m[0], m[1], m[4], m[5]})
+		}
+	}
+	return out
+}
+
+var (
+	dotenvRe = regexp.MustCompile(
+		`(?:(?:dotenv|require\(\s*['"]dotenv['"]\s*\))\.config\(\s*(?:\{[^)]*\bpath\s*:\s*['"]([^'"]+)['"][^)]*)?\)|import\s+['"]dotenv/config['"])`)
+	nodeConfigBindRe = regexp.MustCompile(
+		`(?:(?:const|let|var)\s+(\w+)\s*=\s*require\(\s*['"]config['"]\s*\)|import\s+(?:\*\s+as\s+)?(\w+)\s+from\s+['"]config['"])`)
+	// nodeConfigGetRe matches a get or has on any receiver; scanNodeConfig
+	// keeps those whose receiver is bound to the config package.
+	nodeConfigGetRe = regexp.MustCompile(`\b(\w+)\.(?:get|has)\(\s*['"]([^'"]+)['"]`)
+)
+
+// scanNodeConfig reports .env files loaded through dotenv and keys read
+// through the node "config" package, which merges config files with
+// environment variable overrides.
+func scanNodeConfig(content, path string) []Boundary {
+	idx := newLineIndex(content)
+	var out []Boundary
+	add := func(m []int, source, name string) {
+		b := Boundary{
+			File: path, Type: "env_var",
+			Source: source, Variable: name,
+			Validation: genValidation("env_var"),
+			FuzzInputs: genFuzz("env_var"),
+		}
+		idx.span(&b, m[0], m[1])
+		out = append(out, b)
+	}
+	for _, m := range dotenvRe.FindAllStringSubmatchIndex(content, -1) {
+		name := ".env"
+		if m[2] >= 0 {
+			name = content[m[2]:m[3]]
+		}
+		add(m, "dotenv", name)
+	}
+	bound := map[string]bool{}
+	for _, b := range nodeConfigBindRe.FindAllStringSubmatch(content, -1) {
+		bound[b[1]+b[2]] = true
+	}
+	if len(bound) == 0 {
+		return out
+	}
+	for _, m := range nodeConfigGetRe.FindAllStringSubmatchIndex(content, -1) {
+		if bound[content[m[2]:m[3]]] {
+			add(m, "node-config", content[m[4]:m[5]])
+		}
+	}
+	return out
+}
+
+var sfcScriptRe = regexp.MustCompile(`(?is)<script([^>]*)>(.*?)</script>`)
+
+// sfcDetector scans the <script> blocks of Vue and Svelte single-file
+// components with the detectors registered for the block's language. The rest
+// of the file is blanked out rather than removed so that line numbers refer to
+// the original component.
+type sfcDetector struct {
+	exts []string
+}
+
+func (d *sfcDetector) Extensions() []string { return d.exts }
+
+// RulesVersion covers the script block extraction; the blocks themselves are
+// scanned by detectors hashed on their own.
+func (d *sfcDetector) RulesVersion() int { return 1 }
+
+func (d *sfcDetector) Detect(content, path string) []Boundary {
+	var out []Boundary
+	for _, m := range sfcScriptRe.FindAllStringSubmatchIndex(content, -1) {
+		ext := ".js"
+		if attrs := content[m[2]:m[3]]; strings.Contains(attrs, `lang="ts"`) || strings.Contains(attrs, `lang='ts'`) {
+			ext = ".ts"
+		}
+		masked := blankOutside(content, m[4], m[5])
+		for _, det := range detectorsFor(ext) {
+			out = append(out, det.Detect(masked, path)...)
+		}
+	}
+	return out
+}
+
+// blankOutside replaces every byte of content outside [start, end) with a
+// space, keeping newlines so that offsets and line numbers are unchanged.
+func blankOutside(content string, start, end int) string {
+	b := []byte(content)
+	for i := range b {
+		if (i < start || i >= end) && b[i] != '\n' {
+			b[i] = ' '
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,86 @@
+package main
+
+import "testing"
+
+func TestScanJSXAndModuleExtensions(t *testing.T) {
+	for _, ext := range []string{".jsx", ".tsx", ".mjs", ".cjs", ".vue", ".svelte"} {
+		if !Scannable(ext) {
+			t.Errorf("%s should be scannable", ext)
+		}
+	}
+	code := "export default function Search() {\n" +
+		"  const params = new URLSearchParams(window.location.search)\n" +
+		"  const q = params.get('q')\n" +
+		"  return <div>{q}</div>\n}\n"
+	bs := ScanContent(code, "Search.tsx", ".tsx")
+	if len(bs) != 2 {
+		t.Fatalf("want 2 boundaries, got %d: %+v", len(bs), bs)
+	}
+	assertBoundary(t, bs[0], "window.location.search", "http_query", "Browser")
+	assertBoundary(t, bs[1], "q", "http_query", "Browser")
+}
+
+func TestScanBrowserSources(t *testing.T) {
+	code := "const sid = document.cookie\n" +
+		"window.addEventListener('message', (e) => render(e.data))\n" +
+		"const ref = new URL(location.href).searchParams.get('ref')\n"
+	bs := ScanContent(code, "client.mjs", ".mjs")
+	if len(bs) != 4 {
+		t.Fatalf("want 4 boundaries, got %d: %+v", len(bs), bs)
+	}
+	assertBoundary(t, bs[0], "document.cookie", "http_cookie", "Browser")
+	assertBoundary(t, bs[1], "message", "post_message", "Browser")
+	assertBoundary(t, bs[2], "location.href", "http_query", "Browser")
+	assertBoundary(t, bs[3], "ref", "http_query", "Browser")
+}
+
+func TestScanVueScriptBlockLineNumbers(t *testing.T) {
+	code := "<template>\n" +
+		"  <p>{{ req.query.fake }}</p>\n" +
+		"</template>\n" +
+		"<script setup lang=\"ts\">\n" +
+		"const tab = new URLSearchParams(location.search).get('tab')\n" +
+		"</script>\n"
+	bs := ScanContent(code, "Tabs.vue", ".vue")
+	if len(bs) != 2 {
+		t.Fatalf("want 2 boundaries, got %d: %+v", len(bs), bs)
+	}
+	assertBoundary(t, bs[0], "tab", "http_query", "Browser")
+	assertBoundary(t, bs[1], "location.search", "http_query", "Browser")
+	if bs[0].Line != 5 || bs[1].Line != 5 {
+		t.Errorf("want boundaries on line 5, got %d and %d", bs[0].Line, bs[1].Line)
+	}
+	if got := Language(".svelte"); got != "Svelte" {
+		t.Errorf("Language(.svelte) = %q, want Svelte", got)
+	}
+}
+
+func TestScanNodeConfigLoaders(t *testing.T) {
+	code := "require('dotenv').config({ path: '.env.local' })\n" +
+		"const config = require('config')\n" +
+		"const host = config.get('db.host')\n"
+	bs := ScanContent(code, "server.js", ".js")
+	if len(bs) != 2 {
+		t.Fatalf("want 2 boundaries, got %d: %+v", len(bs), bs)
+	}
+	assertBoundary(t, bs[0], ".env.local", "env_var", "dotenv")
+	assertBoundary(t, bs[1], "db.host", "env_var", "node-config")
+
+	bs = ScanContent("import 'dotenv/config'\n", "index.mjs", ".mjs")
+	if len(bs) != 1 {
+		t.Fatalf("want 1 boundary, got %d", len(bs))
+	}
+	assertBoundary(t, bs[0], ".env", "env_var", "dotenv")
+}
+
+func TestScanJSMultipleMatchesPerLine(t *testing.T) {
+	bs := ScanContent("const r = req.query.from + req.query.to\n", "api.js", ".js")
+	if len(bs) != 2 {
+		t.Fatalf("want 2 boundaries, got %d: %+v", len(bs), bs)
+	}
+	assertBoundary(t, bs[0], "from", "http_query", "Express")
+	assertBoundary(t, bs[1], "to", "http_query", "Express")
+	if bs[0].Column != 11 || bs[1].Column != 28 {
+		t.Errorf("columns: want 11 and 28, got %d and %d", bs[0].Column, bs[1].Column)
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type junitSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Errors   int         `xml:"errors,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string         `xml:"name,attr"`
+	ClassName string         `xml:"classname,attr"`
+	Failures  []junitFailure `xml:"failure"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",cdata"`
+}
+
+// WriteJUnit renders rpt as JUnit XML. Every scanned file is a test case and
+// every unguarded boundary in it is a failure of that case, so CI test
+// dashboards list the files that need validation work.
+func WriteJUnit(w io.Writer, rpt Report) error {
+	byFile := map[string][]Boundary{}
+	for _, b := range rpt.Boundaries {
+		if !b.Guarded {
+			byFile[b.File] = append(byFile[b.File], b)
+		}
+	}
+	files := rpt.scanned
+	if len(files) == 0 {
+		// Reports built without a walk only know the files with findings.
+		for _, g := range groupByFile(rpt.Boundaries) {
+			files = append(files, g.path)
+		}
+	}
+
+	suite := junitSuite{Name: "boundaryguard"}
+	for _, f := range files {
+		c := junitCase{Name: f, ClassName: "boundaryguard." + fileLanguage(f)}
+		for _, b := range byFile[f] {
+			c.Failures = append(c.Failures, junitFailure{
+				Message: fmt.Sprintf("%s:%d: unguarded %s boundary %s (%s); suggested rules: %s",
+					b.File, b.Line, b.Type, b.Variable, b.Source, strings.Join(b.Validation, "; ")),
+				Type: b.Severity,
+				Body: junitBody(b),
+			})
+		}
+		if len(c.Failures) > 0 {
+			suite.Failures++
+		}
+		suite.Cases = append(suite.Cases, c)
+	}
+	suite.Tests = len(suite.Cases)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(junitSuites{Suites: []junitSuite{suite}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func junitBody(b Boundary) string {
+	var s strings.Builder
+	fmt.Fprintf(&s, "%s:%d:%d %s %s\n", b.File, b.Line, b.Column, b.Type, b.Variable)
+	if b.Function != "" {
+		fmt.Fprintf(&s, "function: %s\n", b.Function)
+	}
+	fmt.Fprintf(&s, "severity: %s, confidence: %s\n", b.Severity, b.Confidence)
+	if b.Sink != "" {
+		fmt.Fprintf(&s, "reaches %s sink\n", b.Sink)
+	}
+	fmt.Fprintf(&s, "validation:\n  - %s\n", strings.Join(b.Validation, "\n  - "))
+	fmt.Fprintf(&s, "fuzz inputs:\n  - %s\n", strings.Join(b.FuzzInputs, "\n  - "))
+	return s.String()
+}
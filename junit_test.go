@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestWriteJUnit(t *testing.T) {
+	rpt := Report{
+		TotalFiles: 3,
+		Boundaries: []Boundary{
+			{File: "api.go", Line: 4, Type: "http_query", Source: "URL Query", Variable: "q",
+				Severity: "high", Validation: []string{"max length 1024"}, FuzzInputs: []string{`"<x>"`}},
+			{File: "api.go", Line: 9, Type: "http_header", Variable: "X-Id", Guarded: true},
+			{File: "cfg.go", Line: 2, Type: "env_var", Source: "Env Var", Variable: "PORT", Severity: "low"},
+		},
+		scanned: []string{"api.go", "clean.go", "cfg.go"},
+	}
+	var buf strings.Builder
+	if err := WriteJUnit(&buf, rpt); err != nil {
+		t.Fatal(err)
+	}
+	var got junitSuites
+	if err := xml.Unmarshal([]byte(buf.String()), &got); err != nil {
+		t.Fatalf("output is not valid XML: %v\n%s", err, buf.String())
+	}
+	if len(got.Suites) != 1 {
+		t.Fatalf("want 1 suite, got %d", len(got.Suites))
+	}
+	s := got.Suites[0]
+	if s.Tests != 3 || s.Failures != 2 || len(s.Cases) != 3 {
+		t.Fatalf("want 3 tests and 2 failing, got tests=%d failures=%d cases=%d", s.Tests, s.Failures, len(s.Cases))
+	}
+	if n := len(s.Cases[0].Failures); n != 1 {
+		t.Errorf("api.go: guarded boundary should not fail, want 1 failure, got %d", n)
+	}
+	if len(s.Cases[1].Failures) != 0 {
+		t.Error("clean.go should pass")
+	}
+	msg := s.Cases[0].Failures[0].Message
+	for _, want := range []string{"http_query", "q", "max length 1024"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("failure message %q missing %q", msg, want)
+		}
+	}
+	if !strings.Contains(s.Cases[0].Failures[0].Body, `"<x>"`) {
+		t.Error("failure body should list fuzz inputs")
+	}
+}
@@ -7,60 +7,303 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
+// version is reported in machine-readable outputs.
+const version = "0.1.0"
+
 type Report struct {
-	TotalFiles  int        `json:"total_files"`
-	TotalBounds int        `json:"total_boundaries"`
-	Boundaries  []Boundary `json:"boundaries"`
+	Version     string        `json:"version"`
+	RuleSetHash string        `json:"rule_set_hash"`
+	TotalFiles  int           `json:"total_files"`
+	TotalBounds int           `json:"total_boundaries"`
+	DurationMS  int64         `json:"duration_ms"`
+	Summary     Summary       `json:"summary"`
+	Skipped     []SkippedFile `json:"skipped_files"`
+	Spec        []SpecFinding `json:"spec_findings,omitempty"`
+	Boundaries  []Boundary    `json:"boundaries"`
+
+	scanned           []string // every scanned file, in walk order
+	started, finished time.Time
 }
 
 func main() {
 	dir := flag.String("dir", ".", "Directory to scan")
-	format := flag.String("format", "text", "Output: text or json")
+	format := flag.String("format", "text", "Output: text, json, ndjson, csv, html, markdown, junit, gitlab-sast or codeclimate")
 	maxFiles := flag.Int("max-files", 0, "File limit (0=unlimited, free=5)")
 	failFlag := flag.Bool("fail", false, "Exit 1 if boundaries found")
+	minSeverity := flag.String("min-severity", "info", "Only report boundaries at or above: "+strings.Join(severities, ", "))
+	failOn := flag.String("fail-on", "", "Exit 1 if a boundary at or above this severity is found")
+	validators := flag.String("validators", "", "Write a Go file of validation functions for the boundaries found")
+	validatorsPkg := flag.String("validators-pkg", "validate", "Package name of the --validators file")
+	alongside := flag.Bool("validators-alongside", false, "Write validators next to each scanned Go, Python and JS/TS file, in its language")
+	tsStyle := flag.String("ts-validators", "zod", "Validators generated for JS/TS files: zod or guard")
+	tagPatch := flag.String("validate-tags", "", "Write a patch adding go-playground/validator tags to scanned Go request and config structs")
+	specPath := flag.String("spec", "", "OpenAPI 3.x or Swagger 2.0 spec, JSON only (convert YAML first), to cross-check and to take constraints from")
+	schemaOut := flag.String("json-schema", "", "Write a JSON Schema of the constraints suggested for the boundaries found")
+	openapiOut := flag.String("openapi", "", "Write an OpenAPI 3.1 fragment with a parameter per HTTP boundary found")
+	mdBudget := flag.Int("md-budget", defaultMarkdownBudget, "Maximum size in bytes of --format markdown output")
 	flag.Parse()
 
+	minRank := severityRank(*minSeverity)
+	if minRank < 0 {
+		fmt.Fprintf(os.Stderr, "unknown --min-severity %q (want one of %s)\n", *minSeverity, strings.Join(severities, ", "))
+		os.Exit(2)
+	}
+	failRank := len(severities)
+	if *failOn != "" {
+		if failRank = severityRank(*failOn); failRank < 0 {
+			fmt.Fprintf(os.Stderr, "unknown --fail-on %q (want one of %s)\n", *failOn, strings.Join(severities, ", "))
+			os.Exit(2)
+		}
+	}
+
+	// ndjson and csv are written as each file is scanned; every other format
+	// needs the whole report first.
+	var stream BoundaryWriter
+	switch *format {
+	case "ndjson":
+		stream = NewNDJSONWriter(os.Stdout)
+	case "csv":
+		stream = NewCSVWriter(os.Stdout)
+	}
+
+	if stream != nil && (*validators+*tagPatch+*schemaOut+*openapiOut+*specPath != "" || *alongside) {
+		fmt.Fprintf(os.Stderr, "--validators, --validators-alongside, --validate-tags, --json-schema, --openapi and --spec need the whole report and cannot be used with --format %s\n", *format)
+		os.Exit(2)
+	}
+	var spec []BoundaryEntry
+	if *specPath != "" {
+		var err error
+		if spec, err = LoadSpec(*specPath); err != nil {
+			fmt.Fprintln(os.Stderr, "spec:", err)
+			os.Exit(2)
+		}
+	}
+
+	started := time.Now()
 	var all []Boundary
-	n := 0
-	filepath.Walk(*dir, func(p string, info os.FileInfo, err error) error {
-		if err != nil || info.IsDir() {
+	var scanned []string
+	skipped := []SkippedFile{}
+	n, found, worst := 0, 0, -1
+	err := filepath.Walk(*dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			skipped = append(skipped, SkippedFile{p, err.Error()})
+			return nil
+		}
+		if info.IsDir() {
 			return nil
 		}
 		ext := strings.ToLower(filepath.Ext(p))
-		if ext != ".go" && ext != ".py" && ext != ".js" && ext != ".ts" {
+		if !Scannable(ext) {
 			return nil
 		}
 		if *maxFiles > 0 && n >= *maxFiles {
+			skipped = append(skipped, SkippedFile{p, fmt.Sprintf("max-files limit of %d reached", *maxFiles)})
+			return nil
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			skipped = append(skipped, SkippedFile{p, err.Error()})
 			return nil
 		}
 		n++
-		all = append(all, ScanFile(p, ext)...)
+		for _, b := range ScanContent(string(data), p, ext) {
+			// --fail-on judges every finding, including those that
+			// --min-severity hides from the report.
+			r := severityRank(b.Severity)
+			worst = max(worst, r)
+			if r < minRank {
+				continue
+			}
+			found++
+			if stream == nil {
+				all = append(all, b)
+			} else if err := stream.Write(b); err != nil {
+				return err
+			}
+		}
+		if stream != nil {
+			return stream.Flush()
+		}
+		scanned = append(scanned, p)
 		return nil
 	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, *format+" output:", err)
+		os.Exit(2)
+	}
 
-	rpt := Report{TotalFiles: n, TotalBounds: len(all), Boundaries: all}
+	finished := time.Now()
+	rpt := Report{
+		Version:     version,
+		RuleSetHash: RuleSetHash(),
+		TotalFiles:  n,
+		TotalBounds: found,
+		DurationMS:  finished.Sub(started).Milliseconds(),
+		Summary:     Summarize(scanned, all),
+		Skipped:     skipped,
+		Boundaries:  all,
+		scanned:     scanned,
+		started:     started,
+		finished:    finished,
+	}
+
+	// A spec's declared constraints are better than the default suggestions.
+	entries := EntriesFromBoundaries(all)
+	if spec != nil {
+		rpt.Spec = CrossCheck(spec, all)
+		entries = MergeSpec(entries, spec)
+	}
+	writeArtifact("validators", *validators, func() (string, error) {
+		return GenerateValidators(*validatorsPkg, entries)
+	})
+	if *alongside {
+		files, err := GenerateAlongside(entries, *tsStyle)
+		for path, src := range files {
+			if err == nil {
+				err = os.MkdirAll(filepath.Dir(path), 0o755)
+			}
+			if err == nil {
+				err = os.WriteFile(path, []byte(src), 0o644)
+			}
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "validators-alongside:", err)
+			os.Exit(2)
+		}
+	}
+	writeArtifact("validate-tags", *tagPatch, func() (string, error) {
+		return ValidatorTagPatch(all, entries)
+	})
+	writeArtifact("json-schema", *schemaOut, func() (string, error) {
+		var b strings.Builder
+		err := writeJSON(&b, GenerateJSONSchema(entries))
+		return b.String(), err
+	})
+	writeArtifact("openapi", *openapiOut, func() (string, error) {
+		var b strings.Builder
+		err := writeJSON(&b, GenerateOpenAPI(entries))
+		return b.String(), err
+	})
 
-	if *format == "json" {
+	switch *format {
+	case "ndjson", "csv":
+		// Already written during the walk.
+	case "json":
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
 		enc.Encode(rpt)
-	} else {
+	case "html":
+		if err := WriteHTML(os.Stdout, rpt); err != nil {
+			fmt.Fprintln(os.Stderr, "html report:", err)
+			os.Exit(2)
+		}
+	case "markdown":
+		if err := WriteMarkdown(os.Stdout, rpt, *mdBudget); err != nil {
+			fmt.Fprintln(os.Stderr, "markdown report:", err)
+			os.Exit(2)
+		}
+	case "junit":
+		if err := WriteJUnit(os.Stdout, rpt); err != nil {
+			fmt.Fprintln(os.Stderr, "junit report:", err)
+			os.Exit(2)
+		}
+	case "gitlab-sast":
+		if err := WriteGitLabSAST(os.Stdout, rpt); err != nil {
+			fmt.Fprintln(os.Stderr, "gitlab-sast report:", err)
+			os.Exit(2)
+		}
+	case "codeclimate":
+		if err := WriteCodeClimate(os.Stdout, rpt); err != nil {
+			fmt.Fprintln(os.Stderr, "codeclimate report:", err)
+			os.Exit(2)
+		}
+	default:
 		fmt.Println("\U0001f6e1\ufe0f  BoundaryGuard Report")
-		fmt.Printf("   Files scanned: %d | Boundaries found: %d\n\n", rpt.TotalFiles, rpt.TotalBounds)
+		fmt.Printf("   Files scanned: %d | Skipped: %d | Boundaries found: %d | %dms\n\n",
+			rpt.TotalFiles, len(rpt.Skipped), rpt.TotalBounds, rpt.DurationMS)
 		for i, b := range all {
-			fmt.Printf("[%d] %s:%d\n", i+1, b.File, b.Line)
+			fmt.Printf("[%d] %s:%d:%d\n", i+1, b.File, b.Line, b.Column)
 			fmt.Printf("    Type: %s | Source: %s | Var: %s\n", b.Type, b.Source, b.Variable)
+			fmt.Printf("    Severity: %s | Confidence: %s%s\n", b.Severity, b.Confidence, flowNote(b))
+			if b.Function != "" {
+				fmt.Printf("    Func: %s\n", b.Function)
+			}
+			printSnippet(b)
 			fmt.Printf("    Rules: %s\n", strings.Join(b.Validation, "; "))
 			fmt.Printf("    Fuzz:  %s\n\n", strings.Join(b.FuzzInputs, ", "))
 		}
 		if rpt.TotalBounds == 0 {
 			fmt.Println("   No unguarded boundaries found. Clean!")
 		}
+		if len(rpt.Spec) > 0 {
+			fmt.Printf("\n   Spec cross-check: %d finding(s)\n", len(rpt.Spec))
+			for _, f := range rpt.Spec {
+				fmt.Printf("   - %s %s %q: %s", f.Kind, f.Source, f.Name, f.Detail)
+				if f.Location != "" {
+					fmt.Printf(" (%s)", f.Location)
+				}
+				if f.Confidence != "high" {
+					fmt.Printf(" [%s confidence]", f.Confidence)
+				}
+				fmt.Println()
+			}
+		}
 	}
 
-	if *failFlag && len(all) > 0 {
+	if *failFlag && found > 0 {
+		os.Exit(1)
+	}
+	if worst >= failRank {
 		os.Exit(1)
 	}
 }
+
+// writeArtifact writes the output of gen to path, exiting on error. Nothing is
+// done when path is empty, meaning the flag named name was not given.
+func writeArtifact(name, path string, gen func() (string, error)) {
+	if path == "" {
+		return
+	}
+	out, err := gen()
+	if err == nil {
+		err = os.WriteFile(path, []byte(out), 0o644)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+		os.Exit(2)
+	}
+}
+
+// flowNote summarises where b's value goes, for the text report.
+func flowNote(b Boundary) string {
+	var parts []string
+	if b.Sink != "" {
+		parts = append(parts, "reaches "+b.Sink+" sink")
+	}
+	if b.Guarded {
+		parts = append(parts, "guarded")
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " | " + strings.Join(parts, ", ")
+}
+
+// printSnippet prints b's code snippet with line numbers, marking the lines
+// that contain the boundary.
+func printSnippet(b Boundary) {
+	if b.Snippet == "" {
+		return
+	}
+	for i, l := range strings.Split(b.Snippet, "\n") {
+		n := b.SnippetStart + i
+		mark := " "
+		if n >= b.Line && n <= b.EndLine {
+			mark = ">"
+		}
+		fmt.Printf("    %s %4d | %s\n", mark, n, l)
+	}
+}
@@ -50,8 +50,21 @@ func TestTypeScriptSharedPatterns(t *testing.T) {
 	assertBoundary(t, bs[0], "search", "http_query", "Express")
 }
 
+func TestScannableExtensions(t *testing.T) {
+	for _, ext := range []string{".go", ".py", ".js", ".ts", ".rs", ".cs", ".php", ".rb", ".rake"} {
+		if !Scannable(ext) {
+			t.Errorf("%s should be scannable", ext)
+		}
+	}
+	for _, ext := range []string{".md", ".json", ""} {
+		if Scannable(ext) {
+			t.Errorf("%s should not be scannable", ext)
+		}
+	}
+}
+
 func TestValidationRulesPerType(t *testing.T) {
-	for _, typ := range []string{"http_query", "http_header", "env_var"} {
+	for _, typ := range []string{"http_query", "http_path", "http_body", "http_header", "http_cookie", "post_message", "env_var"} {
 		v := genValidation(typ)
 		if len(v) < 3 {
 			t.Errorf("%s: want >=3 rules, got %d", typ, len(v))
@@ -60,7 +73,7 @@ func TestValidationRulesPerType(t *testing.T) {
 }
 
 func TestFuzzPayloadGeneration(t *testing.T) {
-	for _, typ := range []string{"http_query", "http_header", "env_var"} {
+	for _, typ := range []string{"http_query", "http_path", "http_body", "http_header", "http_cookie", "post_message", "env_var"} {
 		f := genFuzz(typ)
 		if len(f) < 5 {
 			t.Errorf("%s: want >=5 fuzz inputs, got %d", typ, len(f))
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// defaultMarkdownBudget keeps the report under GitHub's 65536 character
+// limit for issue and pull request comments.
+const defaultMarkdownBudget = 65000
+
+// overflowReserve is the space kept free for the overflow summary line.
+const overflowReserve = 200
+
+// WriteMarkdown renders rpt as a GitHub-flavoured Markdown PR comment: one
+// table per file, most severe files first, each finding followed by a
+// collapsible block of fuzz inputs. Output stops before budget bytes, and the
+// findings that did not fit are summarised in a final line.
+func WriteMarkdown(w io.Writer, rpt Report, budget int) error {
+	var buf strings.Builder
+	buf.WriteString("## \U0001f6e1\ufe0f BoundaryGuard Report\n\n")
+	fmt.Fprintf(&buf, "Files scanned: %d | Boundaries found: %d\n", rpt.TotalFiles, rpt.TotalBounds)
+	if len(rpt.Boundaries) == 0 {
+		buf.WriteString("\nNo unguarded boundaries found. Clean!\n")
+		_, err := io.WriteString(w, buf.String())
+		return err
+	}
+
+	groups := groupByFile(rpt.Boundaries)
+	shown, omittedFiles := 0, 0
+	for _, g := range groups {
+		head := fmt.Sprintf("\n### `%s`\n\n| Line | Severity | Type | Variable | Suggested rules |\n|---:|---|---|---|---|\n", g.path)
+		var rows, details strings.Builder
+		n := 0
+		for _, b := range g.bs {
+			row := fmt.Sprintf("| %d | %s | %s | `%s` | %s |\n",
+				b.Line, b.Severity, b.Type, mdCell(b.Variable), mdCell(strings.Join(b.Validation, "; ")))
+			det := fmt.Sprintf("<details><summary>Line %d <code>%s</code>: %d fuzz inputs</summary>\n\n```\n%s\n```\n\n</details>\n",
+				b.Line, htmlEscaper.Replace(b.Variable), len(b.FuzzInputs), strings.Join(b.FuzzInputs, "\n"))
+			size := buf.Len() + len(head) + rows.Len() + len(row) + 1 + details.Len() + len(det)
+			if size > budget-overflowReserve {
+				break
+			}
+			rows.WriteString(row)
+			details.WriteString(det)
+			n++
+		}
+		if n == 0 {
+			omittedFiles++
+			continue
+		}
+		buf.WriteString(head)
+		buf.WriteString(rows.String())
+		buf.WriteString("\n")
+		buf.WriteString(details.String())
+		shown += n
+		if n < len(g.bs) {
+			omittedFiles++
+		}
+	}
+	if rest := len(rpt.Boundaries) - shown; rest > 0 {
+		fmt.Fprintf(&buf, "\n> **%d more boundaries in %d files not shown** (comment size budget). Run `boundaryguard --format html` for the full report.\n",
+			rest, omittedFiles)
+	}
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
+
+type fileGroup struct {
+	path string
+	bs   []Boundary
+}
+
+// groupByFile groups bs by file, most severe file first and otherwise in scan
+// order.
+func groupByFile(bs []Boundary) []fileGroup {
+	var out []fileGroup
+	index := map[string]int{}
+	for _, b := range bs {
+		i, ok := index[b.File]
+		if !ok {
+			i = len(out)
+			index[b.File] = i
+			out = append(out, fileGroup{path: b.File})
+		}
+		out[i].bs = append(out[i].bs, b)
+	}
+	worst := func(g fileGroup) int {
+		r := -1
+		for _, b := range g.bs {
+			if s := severityRank(b.Severity); s > r {
+				r = s
+			}
+		}
+		return r
+	}
+	sort.SliceStable(out, func(i, j int) bool { return worst(out[i]) > worst(out[j]) })
+	return out
+}
+
+var htmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// mdCell makes s safe to place in a Markdown table cell.
+func mdCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ", "\r", "").Replace(s)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func mdBoundary(file string, line int, severity string) Boundary {
+	return Boundary{
+		File: file, Line: line, EndLine: line, Type: "http_query", Source: "Express",
+		Variable: "a|b", Severity: severity,
+		Validation: genValidation("http_query"), FuzzInputs: genFuzz("http_query"),
+	}
+}
+
+func TestWriteMarkdownGroupsByFile(t *testing.T) {
+	bs := []Boundary{
+		mdBoundary("low.js", 3, "low"),
+		mdBoundary("hot.js", 9, "critical"),
+		mdBoundary("low.js", 7, "low"),
+	}
+	var buf strings.Builder
+	if err := WriteMarkdown(&buf, Report{TotalFiles: 2, TotalBounds: 3, Boundaries: bs}, defaultMarkdownBudget); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	hot, low := strings.Index(out, "### `hot.js`"), strings.Index(out, "### `low.js`")
+	if hot < 0 || low < 0 || hot > low {
+		t.Errorf("want hot.js section before low.js section:\n%s", out)
+	}
+	if strings.Count(out, "<details>") != 3 {
+		t.Errorf("want 3 details blocks, got %d", strings.Count(out, "<details>"))
+	}
+	if !strings.Contains(out, "| 9 | critical | http_query | `a\\|b` |") {
+		t.Errorf("table row missing or pipe not escaped:\n%s", out)
+	}
+	if strings.Contains(out, "not shown") {
+		t.Error("nothing should overflow with the default budget")
+	}
+}
+
+func TestWriteMarkdownBudget(t *testing.T) {
+	var bs []Boundary
+	for i := 0; i < 50; i++ {
+		bs = append(bs, mdBoundary(fmt.Sprintf("f%d.js", i%5), i+1, "medium"))
+	}
+	const budget = 3000
+	var buf strings.Builder
+	if err := WriteMarkdown(&buf, Report{TotalFiles: 5, TotalBounds: len(bs), Boundaries: bs}, budget); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if len(out) > budget {
+		t.Errorf("output is %d bytes, budget %d", len(out), budget)
+	}
+	shown := strings.Count(out, "<details>")
+	if shown == 0 || shown == len(bs) {
+		t.Fatalf("want some but not all findings shown, got %d", shown)
+	}
+	if !strings.Contains(out, fmt.Sprintf("**%d more boundaries in", len(bs)-shown)) {
+		t.Errorf("missing overflow summary:\n%s", out)
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+import "regexp"
+
+var phpExts = []string{".php"}
+
+var phpRules = []rule{
+	{"http_query", "PHP Superglobal",
+		regexp.MustCompile(`\$_(?:GET|REQUEST)\[\s*['"]([^'"]+)['"]\s*\]`), 1},
+	{"http_body", "PHP Superglobal",
+		regexp.MustCompile(`\$_POST\[\s*['"]([^'"]+)['"]\s*\]`), 1},
+	{"http_cookie", "PHP Superglobal",
+		regexp.MustCompile(`\$_COOKIE\[\s*['"]([^'"]+)['"]\s*\]`), 1},
+	{"http_header", "PHP Superglobal",
+		regexp.MustCompile(`\$_SERVER\[\s*['"](HTTP_\w+)['"]\s*\]`), 1},
+	{"http_query", "Laravel",
+		regexp.MustCompile(`\$request->(?:input|query|get|post)\(\s*['"]([^'"]+)['"]`), 1},
+	{"http_header", "Laravel",
+		regexp.MustCompile(`\$request->header\(\s*['"]([^'"]+)['"]`), 1},
+	{"env_var", "Env Var",
+		regexp.MustCompile(`\b(?:getenv|env)\(\s*['"]([^'"]+)['"]`), 1},
+}
+
+func init() {
+	RegisterDetector("PHP", &regexDetector{exts: phpExts, rules: phpRules})
+	registerFuncDecl(phpExts, regexp.MustCompile(
+		`\bfunction\s+(?P<name>\w+)|Route::(?P<method>get|post|put|patch|delete|any)\(\s*['"](?P<route>[^'"]+)['"]`), false)
+}
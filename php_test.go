@@ -0,0 +1,23 @@
+package main
+
+import "testing"
+
+func TestScanPHP(t *testing.T) {
+	code := "<?php\n" +
+		"$id = $_GET['id'];\n" +
+		"$pw = $_POST[\"password\"];\n" +
+		"$sid = $_COOKIE['session'];\n" +
+		"$ua = $_SERVER['HTTP_USER_AGENT'];\n" +
+		"$name = $request->input('name');\n" +
+		"$key = getenv('APP_KEY');\n"
+	bs := ScanContent(code, "index.php", ".php")
+	if len(bs) != 6 {
+		t.Fatalf("want 6 boundaries, got %d", len(bs))
+	}
+	assertBoundary(t, bs[0], "id", "http_query", "PHP Superglobal")
+	assertBoundary(t, bs[1], "password", "http_body", "PHP Superglobal")
+	assertBoundary(t, bs[2], "session", "http_cookie", "PHP Superglobal")
+	assertBoundary(t, bs[3], "HTTP_USER_AGENT", "http_header", "PHP Superglobal")
+	assertBoundary(t, bs[4], "name", "http_query", "Laravel")
+	assertBoundary(t, bs[5], "APP_KEY", "env_var", "Env Var")
+}
@@ -0,0 +1,352 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// entryGroup is the entries read by one handler, which the Python and
+// TypeScript generators turn into one model each.
+type entryGroup struct {
+	model   string // <Handler>Request
+	handler string
+	entries []BoundaryEntry
+}
+
+// groupEntries groups entries by Handler in order of first appearance,
+// dropping repeated parameter names within a handler.
+func groupEntries(entries []BoundaryEntry) []*entryGroup {
+	var out []*entryGroup
+	byHandler := map[string]*entryGroup{}
+	seen := map[[2]string]bool{}
+	for _, e := range entries {
+		if seen[[2]string{e.Handler, e.ParamName}] {
+			continue
+		}
+		seen[[2]string{e.Handler, e.ParamName}] = true
+		g := byHandler[e.Handler]
+		if g == nil {
+			g = &entryGroup{model: camelName(e.Handler) + "Request", handler: e.Handler}
+			byHandler[e.Handler] = g
+			out = append(out, g)
+		}
+		g.entries = append(g.entries, e)
+	}
+	return out
+}
+
+var pyKeywords = map[string]bool{
+	"False": true, "None": true, "True": true, "and": true, "as": true, "assert": true,
+	"async": true, "await": true, "break": true, "class": true, "continue": true,
+	"def": true, "del": true, "elif": true, "else": true, "except": true,
+	"finally": true, "for": true, "from": true, "global": true, "if": true,
+	"import": true, "in": true, "is": true, "lambda": true, "nonlocal": true,
+	"not": true, "or": true, "pass": true, "raise": true, "return": true,
+	"try": true, "while": true, "with": true, "yield": true,
+}
+
+// pyName converts a parameter name such as "X-Request-Id" into a Python
+// attribute name such as "x_request_id".
+func pyName(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToLower(r))
+		} else if b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+			b.WriteByte('_')
+		}
+	}
+	n := strings.TrimSuffix(b.String(), "_")
+	if n == "" || unicode.IsDigit(rune(n[0])) {
+		n = "p_" + n
+	}
+	if pyKeywords[n] {
+		n += "_"
+	}
+	return n
+}
+
+// pyString quotes s as a Python string literal, raw when that keeps a
+// regex readable.
+func pyString(s string) string {
+	if !strings.ContainsAny(s, "\"\n\r") && !strings.HasSuffix(s, `\`) {
+		return `r"` + s + `"`
+	}
+	return fmt.Sprintf("%q", s)
+}
+
+// GeneratePydantic produces a Python module with one Pydantic v2 model per
+// handler. Length, range and enum constraints map onto Field and Literal;
+// regex patterns and formats are checked by a field_validator using Python's
+// re module, which accepts the RE2 syntax the other generators use. Floats
+// reject NaN and Inf, list items carry their own constraints through
+// Annotated, and object entries become nested models defined before the
+// model that uses them.
+func GeneratePydantic(entries []BoundaryEntry) string {
+	g := &pyGen{needs: map[string]bool{}}
+	for _, grp := range groupEntries(entries) {
+		doc := ""
+		if grp.handler != "" {
+			doc = fmt.Sprintf("Inputs read by %s.", grp.handler)
+		}
+		g.model(grp.model, doc, grp.handler, grp.entries, false)
+	}
+
+	var out strings.Builder
+	out.WriteString("# Code generated by boundaryguard. DO NOT EDIT.\n\n")
+	if g.patterns.Len() > 0 {
+		out.WriteString("import re\n")
+	}
+	if g.needs["unicodedata"] {
+		out.WriteString("import unicodedata\n")
+	}
+	var typing []string
+	for _, n := range []string{"Annotated", "List", "Literal", "Optional"} {
+		if g.needs[n] {
+			typing = append(typing, n)
+		}
+	}
+	if len(typing) > 0 {
+		fmt.Fprintf(&out, "from typing import %s\n", strings.Join(typing, ", "))
+	}
+	if g.patterns.Len() > 0 || g.needs["unicodedata"] || len(typing) > 0 {
+		out.WriteString("\n")
+	}
+	pydantic := []string{"BaseModel"}
+	for _, n := range []string{"Field", "StrictBool", "field_validator"} {
+		if g.needs[n] {
+			pydantic = append(pydantic, n)
+		}
+	}
+	fmt.Fprintf(&out, "from pydantic import %s\n", strings.Join(pydantic, ", "))
+	if g.patterns.Len() > 0 {
+		out.WriteString("\n" + g.patterns.String())
+	}
+	if g.needs["_BIDI_CONTROLS"] {
+		fmt.Fprintf(&out, "\n_BIDI_CONTROLS = frozenset(%q)\n", bidiControls)
+	}
+	if g.needs["_grapheme_len"] {
+		out.WriteString(pyGraphemeLen)
+	}
+	out.WriteString(g.body.String())
+	return out.String()
+}
+
+type pyGen struct {
+	patterns, body strings.Builder
+	needs          map[string]bool
+}
+
+// model writes class name with a field per entry, after the classes of any
+// nested objects. prefix names the module-level regex constants; typed is set
+// for nested models, whose booleans arrive decoded from JSON.
+func (g *pyGen) model(name, doc, prefix string, entries []BoundaryEntry, typed bool) {
+	var cls, validators strings.Builder
+	fmt.Fprintf(&cls, "\n\nclass %s(BaseModel):\n", name)
+	if doc != "" {
+		fmt.Fprintf(&cls, "    \"\"\"%s\"\"\"\n\n", doc)
+	}
+	if len(entries) == 0 {
+		cls.WriteString("    pass\n")
+	}
+	for _, e := range entries {
+		field := pyName(e.ParamName)
+		typ, constraints := g.fieldType(e, name+camelName(e.ParamName), prefix+"_"+field, typed)
+		var args []string
+		if !entryRequired(e) {
+			args = append(args, "default=None")
+		}
+		if field != e.ParamName {
+			args = append(args, fmt.Sprintf("alias=%q", e.ParamName))
+		}
+		args = append(args, constraints...)
+		if !entryRequired(e) {
+			typ = "Optional[" + typ + "]"
+			g.needs["Optional"] = true
+		}
+		switch {
+		case len(args) == 0:
+			fmt.Fprintf(&cls, "    %s: %s\n", field, typ)
+		case len(args) == 1 && args[0] == "default=None":
+			fmt.Fprintf(&cls, "    %s: %s = None\n", field, typ)
+		default:
+			fmt.Fprintf(&cls, "    %s: %s = Field(%s)\n", field, typ, strings.Join(args, ", "))
+			g.needs["Field"] = true
+		}
+		g.validator(&validators, e, field, prefix+"_"+field)
+	}
+	cls.WriteString(validators.String())
+	g.body.WriteString(cls.String())
+}
+
+// fieldType returns the Python annotation and Field constraints for e. cls
+// names the model written for an object entry.
+func (g *pyGen) fieldType(e BoundaryEntry, cls, prefix string, typed bool) (string, []string) {
+	var args []string
+	switch e.DataType {
+	case "int":
+		if e.MinValue != 0 || e.MaxValue != 0 {
+			args = append(args, fmt.Sprintf("ge=%d", e.MinValue), fmt.Sprintf("le=%d", e.MaxValue))
+		}
+		return "int", args
+	case "uint":
+		args = append(args, fmt.Sprintf("ge=%d", max(e.MinValue, 0)))
+		if e.MaxValue > 0 {
+			args = append(args, fmt.Sprintf("le=%d", e.MaxValue))
+		}
+		return "int", args
+	case "float64":
+		args = append(args, "allow_inf_nan=False")
+		if e.MinFloat != 0 || e.MaxFloat != 0 {
+			args = append(args, "ge="+strconv.FormatFloat(e.MinFloat, 'g', -1, 64), "le="+strconv.FormatFloat(e.MaxFloat, 'g', -1, 64))
+		}
+		return "float", args
+	case "bool":
+		// Pydantic's bool also accepts "yes", "on" and 1; raw input must be
+		// exactly "true" or "false", decoded JSON a real boolean.
+		if typed {
+			g.needs["StrictBool"] = true
+			return "StrictBool", nil
+		}
+		g.needs["Literal"] = true
+		return `Literal["true", "false"]`, nil
+	case "list":
+		item := "str"
+		if e.Items != nil {
+			var itemArgs []string
+			item, itemArgs = g.fieldType(*e.Items, cls+"Item", prefix+"_item", typed)
+			if len(itemArgs) > 0 {
+				item = "Annotated[" + item + ", Field(" + strings.Join(itemArgs, ", ") + ")]"
+				g.needs["Annotated"], g.needs["Field"] = true, true
+			}
+		}
+		if e.MinItems > 0 {
+			args = append(args, fmt.Sprintf("min_length=%d", e.MinItems))
+		}
+		if e.MaxItems > 0 {
+			args = append(args, fmt.Sprintf("max_length=%d", e.MaxItems))
+		}
+		g.needs["List"] = true
+		return "List[" + item + "]", args
+	case "object":
+		g.model(cls, "", prefix, e.Fields, true)
+		return cls, nil
+	}
+	if len(e.EnumValues) > 0 {
+		vals := make([]string, len(e.EnumValues))
+		for i, v := range e.EnumValues {
+			vals[i] = fmt.Sprintf("%q", v)
+		}
+		g.needs["Literal"] = true
+		return "Literal[" + strings.Join(vals, ", ") + "]", nil
+	}
+	if lengthUnit(e) != "runes" {
+		return "str", nil // Field counts code points; see stringChecks
+	}
+	if e.MinLength > 0 {
+		args = append(args, fmt.Sprintf("min_length=%d", e.MinLength))
+	}
+	if e.MaxLength > 0 {
+		args = append(args, fmt.Sprintf("max_length=%d", e.MaxLength))
+	}
+	return "str", args
+}
+
+// validator writes a field_validator for the checks Field cannot express:
+// regex patterns, formats and Unicode rules, also on string list items, and
+// list uniqueness.
+func (g *pyGen) validator(w *strings.Builder, e BoundaryEntry, field, prefix string) {
+	var checks strings.Builder
+	switch {
+	case e.DataType == "list":
+		if e.UniqueItems && (e.Items == nil || comparableType(e.Items.DataType)) {
+			fmt.Fprintf(&checks, "        if v is not None and len(set(v)) != len(v):\n")
+			fmt.Fprintf(&checks, "            raise ValueError(%q)\n", e.ParamName+" must not repeat items")
+		}
+		if e.Items != nil && e.Items.DataType == "string" && len(e.Items.EnumValues) == 0 {
+			for _, c := range g.stringChecks(*e.Items, "item", prefix+"_item") {
+				fmt.Fprintf(&checks, "        for item in v or []:\n            if %s:\n", c.cond)
+				fmt.Fprintf(&checks, "                raise ValueError(%q)\n", e.ParamName+" item "+c.msg)
+			}
+		}
+	case e.DataType != "object" && len(e.EnumValues) == 0:
+		for _, c := range g.stringChecks(e, "v", prefix) {
+			fmt.Fprintf(&checks, "        if v is not None and %s:\n", c.cond)
+			fmt.Fprintf(&checks, "            raise ValueError(%q)\n", e.ParamName+" "+c.msg)
+		}
+	}
+	if checks.Len() == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n    @field_validator(%q)\n    @classmethod\n", field)
+	fmt.Fprintf(w, "    def _check_%s(cls, v):\n%s        return v\n", field, checks.String())
+	g.needs["field_validator"] = true
+}
+
+// stringChecks returns the checks on the str x for e's Unicode rules, its
+// lengths in units Field does not count, and its patterns, declaring the
+// module-level names they use.
+func (g *pyGen) stringChecks(e BoundaryEntry, x, prefix string) []textCheck {
+	var out []textCheck
+	if e.DataType == "string" {
+		if e.ValidUTF8 {
+			// A str is always Unicode, but lone surrogates do not encode.
+			out = append(out, textCheck{"any(0xD800 <= ord(c) <= 0xDFFF for c in " + x + ")", "is not valid UTF-8"})
+		}
+		n := ""
+		unit := lengthUnit(e)
+		switch unit {
+		case "bytes":
+			n = "len(" + x + `.encode("utf-8", "surrogatepass"))`
+		case "graphemes":
+			n = "_grapheme_len(" + x + ")"
+			g.needs["_grapheme_len"], g.needs["unicodedata"] = true, true
+		}
+		if n != "" && e.MaxLength > 0 {
+			out = append(out, textCheck{fmt.Sprintf("%s > %d", n, e.MaxLength), fmt.Sprintf("is longer than %d %s", e.MaxLength, unit)})
+		}
+		if n != "" && e.MinLength > 0 {
+			out = append(out, textCheck{fmt.Sprintf("%s < %d", n, e.MinLength), fmt.Sprintf("is shorter than %d %s", e.MinLength, unit)})
+		}
+		if e.NoControl {
+			out = append(out, textCheck{`any(unicodedata.category(c) == "Cc" or c in _BIDI_CONTROLS for c in ` + x + ")",
+				"contains control or bidi formatting characters"})
+			g.needs["_BIDI_CONTROLS"], g.needs["unicodedata"] = true, true
+		}
+		if e.NFC {
+			out = append(out, textCheck{`not unicodedata.is_normalized("NFC", ` + x + ")", "is not NFC normalized"})
+			g.needs["unicodedata"] = true
+		}
+	}
+	for _, p := range entryPatterns(e) {
+		re := "_" + strings.ToUpper(pyName(prefix+"_"+p.suffix))
+		fmt.Fprintf(&g.patterns, "%s = re.compile(%s)\n", re, pyString(p.re))
+		out = append(out, textCheck{"not " + re + ".search(" + x + ")", p.msg})
+	}
+	return out
+}
+
+// pyGraphemeLen is a port of graphemeLen in goTextHelpers, so Python and Go
+// validators agree on the count.
+const pyGraphemeLen = `
+
+def _grapheme_len(s):
+    n, joined, flags = 0, False, 0
+    for c in s:
+        o = ord(c)
+        attached = unicodedata.category(c) in ("Mn", "Mc", "Me") or 0xFE00 <= o <= 0xFE0F or 0x1F3FB <= o <= 0x1F3FF
+        if o == 0x200D:
+            joined = True
+            continue
+        if 0x1F1E6 <= o <= 0x1F1FF:
+            flags += 1
+            attached = flags % 2 == 0
+        else:
+            flags = 0
+        if n == 0 or not attached and not joined:
+            n += 1
+        joined = False
+    return n
+`
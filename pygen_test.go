@@ -0,0 +1,120 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPyName(t *testing.T) {
+	for in, want := range map[string]string{
+		"user_name":    "user_name",
+		"X-Request-Id": "x_request_id",
+		"class":        "class_",
+		"2fa":          "p_2fa",
+		"user[name]":   "user_name",
+	} {
+		if got := pyName(in); got != want {
+			t.Errorf("pyName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestGeneratePydantic(t *testing.T) {
+	out := GeneratePydantic([]BoundaryEntry{
+		{ParamName: "email", DataType: "string", MinLength: 1, MaxLength: 256, RegexPattern: `^[^@]+@[^@]+$`, Handler: "create_user"},
+		{ParamName: "age", DataType: "int", MinValue: 0, MaxValue: 150, Handler: "create_user"},
+		{ParamName: "X-Request-Id", DataType: "string", MaxLength: 64},
+		{ParamName: "role", DataType: "enum", EnumValues: []string{"admin", "user"}},
+	})
+	for _, want := range []string{
+		"import re\nfrom typing import Literal, Optional\n\nfrom pydantic import BaseModel, Field, field_validator\n",
+		`_CREATE_USER_EMAIL_PATTERN = re.compile(r"^[^@]+@[^@]+$")`,
+		"class CreateUserRequest(BaseModel):\n",
+		// Lengths count UTF-8 bytes, as in the Go validators, not code points.
+		"    email: str\n",
+		`        if v is not None and len(v.encode("utf-8", "surrogatepass")) > 256:`,
+		"    age: Optional[int] = Field(default=None, ge=0, le=150)\n",
+		"    @field_validator(\"email\")\n    @classmethod\n    def _check_email(cls, v):\n",
+		"class Request(BaseModel):\n",
+		`    x_request_id: Optional[str] = Field(default=None, alias="X-Request-Id")`,
+		`    role: Optional[Literal["admin", "user"]] = None`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestGeneratePydanticMinimalImports(t *testing.T) {
+	out := GeneratePydantic([]BoundaryEntry{{ParamName: "q", DataType: "string", MinLength: 1, LengthUnit: "runes"}})
+	if strings.Contains(out, "import re") || strings.Contains(out, "typing") || strings.Contains(out, "field_validator") {
+		t.Errorf("unused imports:\n%s", out)
+	}
+}
+
+func TestGeneratePydanticNested(t *testing.T) {
+	out := GeneratePydantic([]BoundaryEntry{
+		{ParamName: "tags", DataType: "list", MaxItems: 5, UniqueItems: true, Items: &BoundaryEntry{DataType: "string", MaxLength: 8, LengthUnit: "runes"}, Handler: "h"},
+		{ParamName: "debug", DataType: "bool", Handler: "h"},
+		{ParamName: "geo", DataType: "object", Handler: "h", Fields: []BoundaryEntry{
+			{ParamName: "lat", DataType: "float64", MinFloat: -90, MaxFloat: 90},
+			{ParamName: "exact", DataType: "bool"},
+		}},
+	})
+	for _, want := range []string{
+		"from typing import Annotated, List, Literal, Optional\n",
+		"from pydantic import BaseModel, Field, StrictBool, field_validator\n",
+		"class HRequestGeo(BaseModel):\n    lat: Optional[float] = Field(default=None, allow_inf_nan=False, ge=-90, le=90)\n    exact: Optional[StrictBool] = None\n",
+		"    tags: Optional[List[Annotated[str, Field(max_length=8)]]] = Field(default=None, max_length=5)\n",
+		`    debug: Optional[Literal["true", "false"]] = None`,
+		"    geo: Optional[HRequestGeo] = None\n",
+		"        if v is not None and len(set(v)) != len(v):\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "class HRequestGeo") > strings.Index(out, "class HRequest(") {
+		t.Errorf("nested model must be defined first:\n%s", out)
+	}
+}
+
+func TestGeneratePydanticText(t *testing.T) {
+	out := GeneratePydantic([]BoundaryEntry{
+		{ParamName: "name", DataType: "string", MaxLength: 20, LengthUnit: "bytes", NoControl: true, NFC: true, Format: "none"},
+		{ParamName: "nick", DataType: "string", MaxLength: 8, LengthUnit: "runes", Format: "none"},
+	})
+	for _, want := range []string{
+		"import unicodedata\n",
+		`_BIDI_CONTROLS = frozenset("\u061c\u200e`,
+		"    name: Optional[str] = None\n",
+		"    nick: Optional[str] = Field(default=None, max_length=8)\n",
+		`        if v is not None and len(v.encode("utf-8", "surrogatepass")) > 20:`,
+		`        if v is not None and any(unicodedata.category(c) == "Cc" or c in _BIDI_CONTROLS for c in v):`,
+		`        if v is not None and not unicodedata.is_normalized("NFC", v):`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "_grapheme_len") {
+		t.Errorf("unused grapheme helper:\n%s", out)
+	}
+}
+
+func TestGeneratePydanticGraphemes(t *testing.T) {
+	out := GeneratePydantic([]BoundaryEntry{
+		{ParamName: "name", DataType: "string", MaxLength: 20, LengthUnit: "graphemes", Format: "none"},
+	})
+	for _, want := range []string{
+		"import unicodedata\n",
+		"        if v is not None and _grapheme_len(v) > 20:",
+		// ZWJ sequences and flag pairs count once, as in the Go helper.
+		"        if o == 0x200D:\n",
+		"            attached = flags % 2 == 0\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+}
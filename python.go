@@ -0,0 +1,93 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+var pyExts = []string{".py"}
+
+var pyRules = []rule{
+	{"http_query", "Flask/Django",
+		regexp.MustCompile(`request\.(?:args|form|json)(?:\.get\(|\.?\[)['"]([\w]+)`), 1},
+	{"env_var", "Env Var",
+		regexp.MustCompile(`os\.(?:environ\.get|getenv)\(['"]([^'"]+)['"]\)`), 1},
+}
+
+func init() {
+	RegisterDetector("Python", &regexDetector{exts: pyExts, rules: pyRules})
+	RegisterDetector("Python", &funcDetector{exts: pyExts, detect: scanPydanticSettings, version: 1})
+	registerFuncDecl(pyExts, regexp.MustCompile(`^\s*(?:async\s+)?def\s+(?P<name>\w+)`), true)
+}
+
+var (
+	pySettingsClassRe = regexp.MustCompile(`(?m)^([ \t]*)class\s+\w+\s*\([^)]*\bBaseSettings\b[^)]*\)\s*:`)
+	pyFieldRe         = regexp.MustCompile(`^(\w+)\s*:\s*([^=#]+?)\s*(?:=\s*(.*))?$`)
+	pyEnvPrefixRe     = regexp.MustCompile(`env_prefix\s*=\s*['"]([^'"]*)['"]`)
+	pyFieldEnvRe      = regexp.MustCompile(`\b(?:env|alias|validation_alias)\s*=\s*['"]([^'"]+)['"]`)
+)
+
+// scanPydanticSettings reports each annotated field of a pydantic BaseSettings
+// subclass as an env_var boundary named after the variable it is loaded from.
+func scanPydanticSettings(content, path string) []Boundary {
+	lines := strings.Split(content, "\n")
+	idx := newLineIndex(content)
+	var out []Boundary
+	for _, m := range pySettingsClassRe.FindAllStringSubmatchIndex(content, -1) {
+		classIndent := len(content[m[2]:m[3]])
+		start, _ := idx.pos(m[0])
+
+		// Collect the class body: every following line indented deeper than
+		// the class statement, with blank lines allowed in between.
+		var body []int
+		for i := start; i < len(lines); i++ {
+			trimmed := strings.TrimSpace(lines[i])
+			if trimmed == "" {
+				continue
+			}
+			if indentOf(lines[i]) <= classIndent {
+				break
+			}
+			body = append(body, i)
+		}
+		if len(body) == 0 {
+			continue
+		}
+		fieldIndent := indentOf(lines[body[0]])
+
+		prefix := ""
+		for _, i := range body {
+			if p := pyEnvPrefixRe.FindStringSubmatch(lines[i]); p != nil {
+				prefix = p[1]
+			}
+		}
+		for _, i := range body {
+			if indentOf(lines[i]) != fieldIndent {
+				continue
+			}
+			f := pyFieldRe.FindStringSubmatch(strings.TrimSpace(lines[i]))
+			if f == nil || f[1] == "model_config" {
+				continue
+			}
+			name := strings.ToUpper(prefix + f[1])
+			if e := pyFieldEnvRe.FindStringSubmatch(f[3]); e != nil {
+				name = e[1]
+			}
+			b := Boundary{
+				File: path, Type: "env_var",
+				Source: "pydantic", Variable: name,
+				DataType:   strings.TrimSpace(f[2]),
+				Validation: genValidation("env_var"),
+				FuzzInputs: genFuzz("env_var"),
+			}
+			idx.span(&b, idx[i]+fieldIndent, idx[i]+len(strings.TrimRight(lines[i], " \t\r")))
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+// indentOf returns the width of line's leading whitespace.
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
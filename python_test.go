@@ -0,0 +1,26 @@
+package main
+
+import "testing"
+
+func TestScanPydanticSettings(t *testing.T) {
+	code := "from pydantic_settings import BaseSettings, SettingsConfigDict\n\n" +
+		"class Settings(BaseSettings):\n" +
+		"    model_config = SettingsConfigDict(env_prefix='app_')\n\n" +
+		"    database_url: str\n" +
+		"    pool_size: int = 5\n" +
+		"    api_key: SecretStr = Field(..., alias='VENDOR_API_KEY')\n\n" +
+		"    def dsn(self) -> str:\n" +
+		"        return self.database_url\n\n" +
+		"class Other:\n" +
+		"    name: str\n"
+	bs := ScanContent(code, "settings.py", ".py")
+	if len(bs) != 3 {
+		t.Fatalf("want 3 boundaries, got %d: %+v", len(bs), bs)
+	}
+	assertBoundary(t, bs[0], "APP_DATABASE_URL", "env_var", "pydantic")
+	assertBoundary(t, bs[1], "APP_POOL_SIZE", "env_var", "pydantic")
+	assertBoundary(t, bs[2], "VENDOR_API_KEY", "env_var", "pydantic")
+	if bs[1].DataType != "int" || bs[1].Line != 7 {
+		t.Errorf("pool_size: want int on line 7, got %q on line %d", bs[1].DataType, bs[1].Line)
+	}
+}
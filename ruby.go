@@ -0,0 +1,95 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+var rubyExts = []string{".rb", ".rake"}
+
+var rubyRules = []rule{
+	{"http_query", "Rails",
+		regexp.MustCompile(`\bparams\[\s*(?::|['"])(\w+)['"]?\s*\]`), 1},
+	{"http_header", "Rails",
+		regexp.MustCompile(`request\.headers\[\s*['"]([^'"]+)['"]\s*\]`), 1},
+	{"http_cookie", "Rails",
+		regexp.MustCompile(`\bcookies(?:\.signed|\.encrypted|\.permanent)*\[\s*(?::|['"])(\w+)`), 1},
+	{"env_var", "Env Var",
+		regexp.MustCompile(`\bENV(?:\[\s*|\.fetch\(\s*)['"]([^'"]+)['"]`), 1},
+}
+
+var (
+	railsPermitRe    = regexp.MustCompile(`params\.require\(\s*:(\w+)\s*\)\s*\.permit\(([^)]*)\)`)
+	railsPermitKeyRe = regexp.MustCompile(`(?s)^\s*(?::(\w+)|(\w+):\s*(.*?))\s*$`)
+)
+
+func init() {
+	RegisterDetector("Ruby", &regexDetector{exts: rubyExts, rules: rubyRules})
+	RegisterDetector("Ruby", &funcDetector{exts: rubyExts, detect: scanRuby, version: 2})
+	registerFuncDecl(rubyExts, regexp.MustCompile(`^\s*def\s+(?:self\.)?(?P<name>\w+[?!]?)`), true)
+}
+
+// scanRuby expands Rails strong parameters into one boundary per permitted
+// attribute, named the way the form encodes it ("user[email]", or
+// "user[address][city]" for a nested hash).
+func scanRuby(content, path string) []Boundary {
+	idx := newLineIndex(content)
+	var out []Boundary
+	for _, m := range railsPermitRe.FindAllStringSubmatchIndex(content, -1) {
+		model := content[m[2]:m[3]]
+		permitKeys(content[m[4]:m[5]], model, m[4], func(name string, start, end int) {
+			b := Boundary{
+				File: path, Type: "http_body",
+				Source: "Rails", Variable: name,
+				Validation: genValidation("http_body"),
+				FuzzInputs: genFuzz("http_body"),
+			}
+			idx.span(&b, start, end)
+			out = append(out, b)
+		})
+	}
+	return out
+}
+
+// permitKeys calls add with the form name and the offsets, counted from base,
+// of each attribute the permit arguments args allow under prefix. key: [...]
+// lists the attributes of a nested hash, except that key: [] permits an array
+// of scalars and key: {} any hash, both reported as key itself.
+func permitKeys(args, prefix string, base int, add func(name string, start, end int)) {
+	depth, from := 0, 0
+	for i := 0; i <= len(args); i++ {
+		if i < len(args) {
+			switch args[i] {
+			case '[', '{':
+				depth++
+				continue
+			case ']', '}':
+				depth--
+				continue
+			case ',':
+				if depth > 0 {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+		item, start := args[from:i], from
+		from = i + 1
+		k := railsPermitKeyRe.FindStringSubmatchIndex(item)
+		if k == nil {
+			continue
+		}
+		if k[2] >= 0 {
+			add(prefix+"["+item[k[2]:k[3]]+"]", base+start+k[2]-1, base+start+k[3])
+			continue
+		}
+		key, val := item[k[4]:k[5]], item[k[6]:k[7]]
+		name := prefix + "[" + key + "]"
+		if inner := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(val, "["), "]")); strings.HasPrefix(val, "[") && inner != "" {
+			permitKeys(val[1:len(val)-1], name, base+start+k[6]+1, add)
+			continue
+		}
+		add(name, base+start+k[4], base+start+k[5]+1)
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import "testing"
+
+func TestScanRails(t *testing.T) {
+	code := "class UsersController < ApplicationController\n" +
+		"  def show\n" +
+		"    @user = User.find(params[:id])\n" +
+		"    tz = request.headers['X-Timezone']\n" +
+		"    theme = cookies.signed[:theme]\n" +
+		"    host = ENV['APP_HOST']\n" +
+		"  end\n\n" +
+		"  def user_params\n" +
+		"    params.require(:user).permit(:name, :email, tags: [])\n" +
+		"  end\nend\n"
+	bs := ScanContent(code, "app/controllers/users_controller.rb", ".rb")
+	if len(bs) != 7 {
+		t.Fatalf("want 7 boundaries, got %d: %+v", len(bs), bs)
+	}
+	assertBoundary(t, bs[0], "id", "http_query", "Rails")
+	assertBoundary(t, bs[1], "X-Timezone", "http_header", "Rails")
+	assertBoundary(t, bs[2], "theme", "http_cookie", "Rails")
+	assertBoundary(t, bs[3], "APP_HOST", "env_var", "Env Var")
+	assertBoundary(t, bs[4], "user[name]", "http_body", "Rails")
+	assertBoundary(t, bs[5], "user[email]", "http_body", "Rails")
+	assertBoundary(t, bs[6], "user[tags]", "http_body", "Rails")
+}
+
+func TestScanRailsNestedPermit(t *testing.T) {
+	code := "def user_params\n" +
+		"  params.require(:user).permit(:name, address: [:city, :zip, geo: [:lat]],\n" +
+		"    preferences: {}, tags: [])\n" +
+		"end\n"
+	bs := ScanContent(code, "app/controllers/users_controller.rb", ".rb")
+	want := []string{"user[name]", "user[address][city]", "user[address][zip]", "user[address][geo][lat]", "user[preferences]", "user[tags]"}
+	if len(bs) != len(want) {
+		t.Fatalf("want %d boundaries, got %d: %+v", len(want), len(bs), bs)
+	}
+	for i, w := range want {
+		assertBoundary(t, bs[i], w, "http_body", "Rails")
+	}
+	if bs[4].Line != 3 {
+		t.Errorf("user[preferences] on line %d, want 3", bs[4].Line)
+	}
+}
@@ -2,69 +2,222 @@ package main
 
 import (
 	"fmt"
+	"go/token"
+	"path"
+	"regexp"
+	"strconv"
 	"strings"
 )
 
 // BoundaryEntry represents a discovered input boundary with its constraints.
 type BoundaryEntry struct {
 	ParamName    string
-	DataType     string   // "string", "int", "enum"
-	MaxLength    int      // max string length (0 = no limit)
-	MinLength    int      // min string length (0 = no limit)
-	MinValue     int64    // integer lower bound
-	MaxValue     int64    // integer upper bound
-	EnumValues   []string // allowed values for enum validation
-	RegexPattern string   // regex pattern the value must match
+	DataType     string          // "string", "int", "uint", "float64", "bool", "enum", "list", "object"
+	MaxLength    int             // max string length (0 = no limit)
+	MinLength    int             // min string length (0 = no limit)
+	LengthUnit   string          // unit of MaxLength and MinLength: "bytes" (default), "runes" or "graphemes"
+	ValidUTF8    bool            // reject invalid UTF-8
+	NFC          bool            // require Unicode Normalization Form C
+	NoControl    bool            // reject control and bidi formatting characters
+	MinValue     int64           // integer lower bound (MinValue and MaxValue both 0 = no range)
+	MaxValue     int64           // integer upper bound
+	MinFloat     float64         // float64 lower bound (MinFloat and MaxFloat both 0 = no range)
+	MaxFloat     float64         // float64 upper bound
+	MinItems     int             // min list length (0 = no limit)
+	MaxItems     int             // max list length (0 = no limit)
+	UniqueItems  bool            // list items must not repeat
+	Items        *BoundaryEntry  // rule for each list item; its ParamName is ignored
+	Fields       []BoundaryEntry // members of an "object", keyed by ParamName
+	EnumValues   []string        // allowed values for enum validation
+	RegexPattern string          // regex pattern the value must match
+	Handler      string          // function that reads the input, if known
+	Source       string          // boundary type: "http_query", "http_header", ...
+	File         string          // source file the input was found in, if known
+	Sink         string          // dangerous use the input reaches, as in Boundary.Sink
+	Format       string          // semantic format, see FormatNames; "" infers one from ParamName, "none" disables
+	Schemes      []string        // URL schemes allowed by Format "url" (default http, https)
 }
 
-// ValidationRule holds a generated Go validation code snippet.
+// ValidationRule holds a generated Go validation code snippet. Snippets
+// check the value in a variable named after ParamName (see snippetVar),
+// return a *ValidationError and may call graphemeLen or unsafeRune, all
+// declared by GenerateValidators.
 type ValidationRule struct {
 	ParamName string
-	RuleType  string // "length", "range", "regex", "enum"
+	RuleType  string // "length", "text", "range", "bool", "items", "unique", "regex", "enum", "format", "sanitize"
 	GoCode    string
 }
 
-// GenerateRules produces Go validation code snippets from discovered boundary entries.
+// GenerateRules produces Go validation code snippets from discovered boundary
+// entries. Constraints that cannot be generated, such as an unknown format,
+// are left out; GenerateRulesChecked reports them instead.
 func GenerateRules(entries []BoundaryEntry) []ValidationRule {
 	var out []ValidationRule
 	for _, e := range entries {
-		out = append(out, rulesForEntry(e)...)
+		rules, _ := rulesFor(e, snippetVar(e.ParamName), e.ParamName, false)
+		out = append(out, rules...)
 	}
 	return out
 }
 
-func rulesForEntry(e BoundaryEntry) []ValidationRule {
+// GenerateRulesChecked is GenerateRules, but like GenerateValidators it
+// rejects unknown formats and length units and invalid regexes.
+func GenerateRulesChecked(entries []BoundaryEntry) ([]ValidationRule, error) {
 	var out []ValidationRule
+	for _, e := range entries {
+		rules, err := rulesFor(e, snippetVar(e.ParamName), e.ParamName, false)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, rules...)
+	}
+	return out, nil
+}
+
+// snippetReserved are the names a snippet's variable must not take: the
+// packages snippets call, which the variable would shadow, and the locals
+// they declare.
+var snippetReserved = func() map[string]bool {
+	m := map[string]bool{
+		"fmt": true, "math": true, "regexp": true, "strings": true, "utf8": true,
+		"unicode": true, "norm": true, "html": true, "template": true, "filepath": true,
+		"exec": true, "ctx": true, "db": true, "root": true,
+		"a": true, "u": true, "err": true, "i": true, "item": true, "seen": true, "matched": true,
+	}
+	for _, f := range formats {
+		for _, imp := range f.imports {
+			m[path.Base(imp)] = true
+		}
+	}
+	return m
+}()
+
+// snippetVar returns the Go variable that snippets for param check: param
+// itself when it is a usable identifier, otherwise its lower camel case, with
+// "Value" appended when that would shadow a package the snippet calls, as
+// "url" would for url.Parse.
+func snippetVar(param string) string {
+	v := param
+	if !token.IsIdentifier(v) {
+		v = lowerFirst(fieldName(param))
+	}
+	if snippetReserved[v] || token.IsKeyword(v) {
+		v += "Value"
+	}
+	return v
+}
+
+// rulesFor returns the rules for e checking the Go expression v and reporting
+// failures against field. List items and object fields recurse with their own
+// expression and field path; typed is set below an object, where JSON has
+// already decoded booleans. A constraint that cannot be generated is left
+// out, and the first such problem is returned with the other rules.
+func rulesFor(e BoundaryEntry, v, field string, typed bool) ([]ValidationRule, error) {
+	var out []ValidationRule
+	var err error
+	fail := func(problem error) {
+		if err == nil {
+			err = problem
+		}
+	}
+	add := func(ruleType, code string, args ...any) {
+		out = append(out, ValidationRule{ParamName: field, RuleType: ruleType, GoCode: fmt.Sprintf(code, args...)})
+	}
+	unitErr := checkLengthUnit(e, field)
+	fail(unitErr)
 
 	switch e.DataType {
 	case "string":
-		if e.MaxLength > 0 {
-			out = append(out, ValidationRule{
-				ParamName: e.ParamName,
-				RuleType:  "length",
-				GoCode: fmt.Sprintf(
-					"if len(%s) > %d {\n\treturn fmt.Errorf(\"%s exceeds max length %d\")\n}",
-					e.ParamName, e.MaxLength, e.ParamName, e.MaxLength),
-			})
-		}
-		if e.MinLength > 0 {
-			out = append(out, ValidationRule{
-				ParamName: e.ParamName,
-				RuleType:  "length",
-				GoCode: fmt.Sprintf(
-					"if len(%s) < %d {\n\treturn fmt.Errorf(\"%s must be at least %d characters\")\n}",
-					e.ParamName, e.MinLength, e.ParamName, e.MinLength),
-			})
+		if e.ValidUTF8 {
+			add("text", "if !utf8.ValidString(%s) {\n\treturn &ValidationError{Field: %q, Rule: \"utf8\", Got: %s}\n}",
+				v, field, v)
+		}
+		n := goLength(e.LengthUnit, v)
+		if e.MaxLength > 0 && unitErr == nil {
+			add("length", "if %s > %d {\n\treturn &ValidationError{Field: %q, Rule: \"max_length\", Limit: %d, Got: %s}\n}",
+				n, e.MaxLength, field, e.MaxLength, n)
+		}
+		if e.MinLength > 0 && unitErr == nil {
+			add("length", "if %s < %d {\n\treturn &ValidationError{Field: %q, Rule: \"min_length\", Limit: %d, Got: %s}\n}",
+				n, e.MinLength, field, e.MinLength, n)
+		}
+		if e.NoControl {
+			add("text", "if %s {\n\treturn &ValidationError{Field: %q, Rule: \"control_chars\", Got: %s}\n}",
+				goUnsafeText(v), field, v)
+		}
+		if e.NFC {
+			add("text", "if !norm.NFC.IsNormalString(%s) {\n\treturn &ValidationError{Field: %q, Rule: \"nfc\", Got: %s}\n}",
+				v, field, v)
 		}
 	case "int":
-		out = append(out, ValidationRule{
-			ParamName: e.ParamName,
-			RuleType:  "range",
-			GoCode: fmt.Sprintf(
-				"if %s < %d || %s > %d {\n\treturn fmt.Errorf(\"%s must be between %d and %d\")\n}",
-				e.ParamName, e.MinValue, e.ParamName, e.MaxValue,
-				e.ParamName, e.MinValue, e.MaxValue),
-		})
+		// MinValue and MaxValue both 0 means no range, as in the other
+		// generators.
+		if e.MinValue != 0 || e.MaxValue != 0 {
+			add("range", "if %s < %d {\n\treturn &ValidationError{Field: %q, Rule: \"min\", Limit: %d, Got: %s}\n}\n"+
+				"if %s > %d {\n\treturn &ValidationError{Field: %q, Rule: \"max\", Limit: %d, Got: %s}\n}",
+				v, e.MinValue, field, e.MinValue, v, v, e.MaxValue, field, e.MaxValue, v)
+		}
+	case "uint":
+		// Unsigned values cannot go below zero, so only a positive lower
+		// bound needs a check.
+		if e.MinValue > 0 {
+			add("range", "if %s < %d {\n\treturn &ValidationError{Field: %q, Rule: \"min\", Limit: uint64(%d), Got: %s}\n}",
+				v, e.MinValue, field, e.MinValue, v)
+		}
+		if e.MaxValue > 0 {
+			add("range", "if %s > %d {\n\treturn &ValidationError{Field: %q, Rule: \"max\", Limit: uint64(%d), Got: %s}\n}",
+				v, e.MaxValue, field, e.MaxValue, v)
+		}
+	case "float64":
+		add("range", "if math.IsNaN(%s) || math.IsInf(%s, 0) {\n\treturn &ValidationError{Field: %q, Rule: \"finite\", Got: %s}\n}",
+			v, v, field, v)
+		if e.MinFloat != 0 || e.MaxFloat != 0 {
+			lo, hi := goFloatLit(e.MinFloat), goFloatLit(e.MaxFloat)
+			add("range", "if %s < %s {\n\treturn &ValidationError{Field: %q, Rule: \"min\", Limit: %s, Got: %s}\n}\n"+
+				"if %s > %s {\n\treturn &ValidationError{Field: %q, Rule: \"max\", Limit: %s, Got: %s}\n}",
+				v, lo, field, lo, v, v, hi, field, hi, v)
+		}
+	case "bool":
+		if typed {
+			break
+		}
+		// Raw input is accepted only as the exact strings strconv.FormatBool
+		// produces; "1", "yes" or "TRUE" are rejected rather than guessed.
+		add("bool", "if %s != \"true\" && %s != \"false\" {\n\treturn &ValidationError{Field: %q, Rule: \"bool\", Limit: []string{\"true\", \"false\"}, Got: %s}\n}",
+			v, v, field, v)
+	case "list":
+		if e.MinItems > 0 {
+			add("items", "if len(%s) < %d {\n\treturn &ValidationError{Field: %q, Rule: \"min_items\", Limit: %d, Got: len(%s)}\n}",
+				v, e.MinItems, field, e.MinItems, v)
+		}
+		if e.MaxItems > 0 {
+			add("items", "if len(%s) > %d {\n\treturn &ValidationError{Field: %q, Rule: \"max_items\", Limit: %d, Got: len(%s)}\n}",
+				v, e.MaxItems, field, e.MaxItems, v)
+		}
+		if e.Items == nil {
+			return out, err
+		}
+		item := *e.Items
+		if e.UniqueItems && comparableType(item.DataType) {
+			add("unique", "seen := make(map[%s]bool, len(%s))\nfor i, item := range %s {\n"+
+				"\tif seen[item] {\n\t\treturn &ValidationError{Field: fmt.Sprintf(\"%s[%%d]\", i), Rule: \"unique\", Got: item}\n\t}\n"+
+				"\tseen[item] = true\n}",
+				validatorType(item.DataType, typed), v, v, field)
+		}
+		rules, itemErr := rulesFor(item, "item", field+"[]", typed)
+		fail(itemErr)
+		for _, r := range rules {
+			r.GoCode = "for _, item := range " + v + " {\n\t" + strings.ReplaceAll(r.GoCode, "\n", "\n\t") + "\n}"
+			out = append(out, r)
+		}
+		return out, err
+	case "object":
+		for _, f := range e.Fields {
+			rules, fieldErr := rulesFor(f, v+"."+fieldName(f.ParamName), field+"."+f.ParamName, true)
+			fail(fieldErr)
+			out = append(out, rules...)
+		}
+		return out, err
 	}
 
 	// Enum rules apply regardless of DataType (a string or enum field can have allowed values)
@@ -74,26 +227,127 @@ func rulesForEntry(e BoundaryEntry) []ValidationRule {
 			quoted[i] = fmt.Sprintf("%q", v)
 		}
 		cases := strings.Join(quoted, ", ")
-		display := strings.Join(e.EnumValues, ", ")
-		out = append(out, ValidationRule{
-			ParamName: e.ParamName,
-			RuleType:  "enum",
-			GoCode: fmt.Sprintf(
-				"switch %s {\ncase %s:\n\t// valid\ndefault:\n\treturn fmt.Errorf(\"%s must be one of [%s]\")\n}",
-				e.ParamName, cases, e.ParamName, display),
-		})
+		add("enum", "switch %s {\ncase %s:\n\t// valid\ndefault:\n\treturn &ValidationError{Field: %q, Rule: \"enum\", Limit: []string{%s}, Got: %s}\n}",
+			v, cases, field, cases, v)
 	}
 
 	// Regex rules
-	if e.RegexPattern != "" {
-		out = append(out, ValidationRule{
-			ParamName: e.ParamName,
-			RuleType:  "regex",
-			GoCode: fmt.Sprintf(
-				"if matched, _ := regexp.MatchString(%q, %s); !matched {\n\treturn fmt.Errorf(\"%s does not match required pattern\")\n}",
-				e.RegexPattern, e.ParamName, e.ParamName),
-		})
+	if _, reErr := regexp.Compile(e.RegexPattern); reErr != nil {
+		fail(fmt.Errorf("%s: %w", field, reErr))
+	} else if e.RegexPattern != "" {
+		add("regex", "if matched, _ := regexp.MatchString(%q, %s); !matched {\n\treturn &ValidationError{Field: %q, Rule: \"regex\", Limit: %q, Got: %s}\n}",
+			e.RegexPattern, v, field, e.RegexPattern, v)
+	}
+
+	// Format rules
+	format := entryFormat(e)
+	if _, ok := formats[format]; format != "" && !ok {
+		fail(fmt.Errorf("%s: unknown format %q", field, format))
+	} else if format != "" {
+		re := ""
+		if formatUsesPattern(format) {
+			re = fmt.Sprintf("regexp.MustCompile(%q)", formatPattern(format, entrySchemes(e)))
+		}
+		add("format", "if %s {\n\treturn &ValidationError{Field: %q, Rule: \"format\", Limit: %q, Got: %s}\n}",
+			formatCondition(e, v, re), field, format, v)
+	}
+
+	// Sanitizer rules come last: they make an already valid value safe for
+	// the sink it reaches.
+	if r, ok := sanitizerRule(e, v, field); ok {
+		out = append(out, r)
 	}
 
+	return out, err
+}
+
+// comparableType reports whether values of DataType t can key a Go map, which
+// the uniqueness check for lists needs.
+func comparableType(t string) bool {
+	return t != "list" && t != "object"
+}
+
+// goFloatLit formats f as a Go float64 constant expression.
+func goFloatLit(f float64) string {
+	return "float64(" + strconv.FormatFloat(f, 'g', -1, 64) + ")"
+}
+
+// EntriesFromBoundaries turns scanned boundaries into entries for the rule
+// generators, one per variable and enclosing function in each file. Strings
+// get the report's default suggestions of non-empty and at most 1024 bytes;
+// lists of a known scalar type get a rule for their items. An entry keeps the
+// first sink any of its reads reaches.
+func EntriesFromBoundaries(bs []Boundary) []BoundaryEntry {
+	var out []BoundaryEntry
+	seen := map[[3]string]int{}
+	for _, b := range bs {
+		key := [3]string{b.File, b.Function, b.Variable}
+		if i, ok := seen[key]; ok {
+			if out[i].Sink == "" {
+				out[i].Sink = b.Sink
+			}
+			continue
+		}
+		seen[key] = len(out)
+		e := BoundaryEntry{ParamName: b.Variable, Handler: b.Function, Source: b.Type, File: b.File, Sink: b.Sink}
+		typeEntry(&e, b.DataType, b.Optional)
+		out = append(out, e)
+	}
 	return out
 }
+
+// listElem matches the list spellings of the scanned languages: Go slices,
+// Python List[T] and list[T], and Rust Vec<T>.
+var listElem = regexp.MustCompile(`^(?:\[\](.+)|[Ll]ist\[(.+)\]|Vec<(.+)>)$`)
+
+// optionalType matches the optional and nullable spellings of the scanned
+// languages: Go pointers, Rust Option<T>, C# Nullable<T> and T?, and Python
+// Optional[T], T | None and None | T.
+var optionalType = regexp.MustCompile(`^(?:\*(.+)|Option<(.+)>|Nullable<(.+)>|Optional\[(.+)\]|(.+)\?|(.+?)\s*\|\s*None|None\s*\|\s*(.+))$`)
+
+// typeEntry sets e's DataType, and the default constraints for it, from a
+// scanned type name. Unknown types, such as structs, are treated as strings
+// at the top level and left without an item rule in lists. Strings must be
+// non-empty unless optional is set or the type is optional or nullable.
+func typeEntry(e *BoundaryEntry, dataType string, optional bool) {
+	t := dataType
+	if m := optionalType.FindStringSubmatch(t); m != nil {
+		t, optional = strings.Join(m[1:], ""), true
+	}
+	if m := listElem.FindStringSubmatch(t); m != nil {
+		e.DataType = "list"
+		item := BoundaryEntry{Source: e.Source}
+		if item.DataType = scalarKind(m[1] + m[2] + m[3]); item.DataType != "" {
+			if item.DataType == "string" {
+				item.MaxLength = 1024
+			}
+			e.Items = &item
+		}
+		return
+	}
+	e.DataType = scalarKind(t)
+	if e.DataType == "" || e.DataType == "string" {
+		e.DataType, e.MaxLength = "string", 1024
+		if !optional {
+			e.MinLength = 1
+		}
+	}
+}
+
+// scalarKind returns the entry DataType for a scalar type name, or "" if t
+// is not one.
+func scalarKind(t string) string {
+	switch t {
+	case "int", "int8", "int16", "int32", "int64", "i8", "i16", "i32", "i64", "isize", "long":
+		return "int"
+	case "uint", "uint8", "uint16", "uint32", "uint64", "u8", "u16", "u32", "u64", "usize", "ulong":
+		return "uint"
+	case "float32", "float64", "float", "f32", "f64", "double", "decimal":
+		return "float64"
+	case "bool", "boolean":
+		return "bool"
+	case "string", "str", "String", "&str":
+		return "string"
+	}
+	return ""
+}
@@ -28,6 +28,13 @@ func TestRuleGenStringMaxLength(t *testing.T) {
 	}
 }
 
+func TestRuleGenUnboundedInt(t *testing.T) {
+	// Scanned ints carry no bounds, which must not become "> 0".
+	if rules := GenerateRules(EntriesFromBoundaries([]Boundary{{Variable: "port", Type: "env_var", DataType: "int"}})); len(rules) != 0 {
+		t.Errorf("unbounded int: want no rules, got %+v", rules)
+	}
+}
+
 func TestRuleGenStringMinAndMaxLength(t *testing.T) {
 	entries := []BoundaryEntry{
 		{ParamName: "password", DataType: "string", MinLength: 8, MaxLength: 128},
@@ -172,3 +179,77 @@ func TestRuleGenMultipleEntries(t *testing.T) {
 		}
 	}
 }
+
+func TestRuleGenFloatBoolAndUint(t *testing.T) {
+	rules := GenerateRules([]BoundaryEntry{
+		{ParamName: "lat", DataType: "float64", MinFloat: -90, MaxFloat: 90.5},
+		{ParamName: "debug", DataType: "bool"},
+		{ParamName: "limit", DataType: "uint", MaxValue: 500},
+	})
+	var code strings.Builder
+	for _, r := range rules {
+		code.WriteString(r.GoCode + "\n")
+	}
+	for _, want := range []string{
+		"if math.IsNaN(lat) || math.IsInf(lat, 0) {",
+		`Rule: "finite"`,
+		"if lat < float64(-90) {",
+		"if lat > float64(90.5) {",
+		`if debug != "true" && debug != "false" {`,
+		`Limit: uint64(500)`,
+	} {
+		if !strings.Contains(code.String(), want) {
+			t.Errorf("missing %q in:\n%s", want, code.String())
+		}
+	}
+	if strings.Contains(code.String(), "limit < ") {
+		t.Errorf("uint without a lower bound should not be checked against it:\n%s", code.String())
+	}
+}
+
+func TestRuleGenListAndObject(t *testing.T) {
+	rules := GenerateRules([]BoundaryEntry{{
+		ParamName: "user", DataType: "object",
+		Fields: []BoundaryEntry{
+			{ParamName: "tags", DataType: "list", MaxItems: 5, UniqueItems: true,
+				Items: &BoundaryEntry{DataType: "string", MaxLength: 16}},
+			{ParamName: "admin", DataType: "bool"},
+		},
+	}})
+	got := map[string]string{}
+	for _, r := range rules {
+		got[r.ParamName+" "+r.RuleType] += r.GoCode
+	}
+	for key, want := range map[string]string{
+		"user.tags items":    "if len(user.Tags) > 5 {",
+		"user.tags unique":   `Field: fmt.Sprintf("user.tags[%d]", i), Rule: "unique"`,
+		"user.tags[] length": "for _, item := range user.Tags {\n\tif len(item) > 16 {",
+	} {
+		if !strings.Contains(got[key], want) {
+			t.Errorf("%s: missing %q in %q", key, want, got[key])
+		}
+	}
+	if _, ok := got["user.admin bool"]; ok {
+		t.Error("decoded JSON booleans need no parsing check")
+	}
+}
+
+func TestRuleGenText(t *testing.T) {
+	rules := GenerateRules([]BoundaryEntry{
+		{ParamName: "name", DataType: "string", MaxLength: 32, LengthUnit: "runes", ValidUTF8: true, NoControl: true, NFC: true},
+	})
+	var code strings.Builder
+	for _, r := range rules {
+		code.WriteString(r.RuleType + ": " + r.GoCode + "\n")
+	}
+	for _, want := range []string{
+		"text: if !utf8.ValidString(name) {",
+		"length: if utf8.RuneCountInString(name) > 32 {",
+		"text: if strings.IndexFunc(name, unsafeRune) >= 0 {",
+		"text: if !norm.NFC.IsNormalString(name) {",
+	} {
+		if !strings.Contains(code.String(), want) {
+			t.Errorf("missing %q in:\n%s", want, code.String())
+		}
+	}
+}
@@ -0,0 +1,430 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+var rustExts = []string{".rs"}
+
+var rustRules = []rule{
+	{"env_var", "Env Var",
+		regexp.MustCompile(`env::var(?:_os)?\("([^"]+)"\)`), 1},
+	{"env_var", "CLI Args",
+		regexp.MustCompile(`env::(args(?:_os)?)\(\)`), 1},
+}
+
+func init() {
+	RegisterDetector("Rust", &regexDetector{exts: rustExts, rules: rustRules})
+	RegisterDetector("Rust", &funcDetector{exts: rustExts, detect: scanRust, version: 2})
+	registerFuncDecl(rustExts, regexp.MustCompile(`\bfn\s+(?P<name>\w+)`), false)
+}
+
+// rustField is a named field of a struct defined in the scanned crate.
+type rustField struct {
+	name string
+	typ  string
+}
+
+var (
+	rustExtractorRe = regexp.MustCompile(
+		`(\w+|\w+\s*\(\s*\(?[\w\s,]*\)?\s*\))\s*:\s*(web::)?(Query|Path|Json|Form)\s*<\s*(\([^)]*\)|[\w:]+)`)
+	rustHeaderMapRe = regexp.MustCompile(`(\w+)\s*:\s*(?:axum::http::|http::)?HeaderMap\b`)
+	rustHeaderGetRe = regexp.MustCompile(`\b(\w+)\s*\.get\(\s*"([^"]+)"`)
+	rustReqHeaderRe = regexp.MustCompile(`\.headers\(\)\.get\(\s*"([^"]+)"`)
+	rustStructRe    = regexp.MustCompile(`(?s)struct\s+(\w+)\s*(?:<[^>{]*>)?\s*\{(.*?)\n\s*\}`)
+	rustFieldRe     = regexp.MustCompile(`^\s*(?:pub(?:\([^)]*\))?\s+)?(\w+)\s*:\s*(\S.*)$`)
+	rustRenameRe    = regexp.MustCompile(`#\[serde\([^)]*rename\s*=\s*"([^"]+)"`)
+	rustUseRe       = regexp.MustCompile(`(?m)^\s*(?:pub(?:\([^)]*\))?\s+)?use\s+([\w:]+?)(::\{[^{}]*\}|::\*|\s+as\s+\w+)?\s*;`)
+)
+
+var rustExtractorTypes = map[string]string{
+	"Query": "http_query",
+	"Path":  "http_path",
+	"Json":  "http_body",
+	"Form":  "http_body",
+}
+
+// rustFramework names the web framework content imports, or "" if none.
+func rustFramework(content string) string {
+	switch {
+	case strings.Contains(content, "actix_web"):
+		return "actix-web"
+	case strings.Contains(content, "axum"):
+		return "axum"
+	}
+	return ""
+}
+
+// scanRust finds axum and actix-web extractor parameters and header reads.
+// Extracted struct types are expanded into one boundary per field when the
+// struct is defined in the same file or elsewhere in the crate containing
+// path.
+func scanRust(content, path string) []Boundary {
+	mods, mod := crateStructs(path)
+	structs := newRustResolver(content, mod, mods)
+	framework := rustFramework(content)
+	if framework == "" {
+		framework = "axum"
+	}
+
+	idx := newLineIndex(content)
+	var out []Boundary
+	for _, m := range rustExtractorRe.FindAllStringSubmatchIndex(content, -1) {
+		binding := content[m[2]:m[3]]
+		kind := content[m[6]:m[7]]
+		typ := content[m[8]:m[9]]
+		src := framework
+		if m[4] >= 0 {
+			src = "actix-web"
+		}
+		if fields, ok := structs.fields(typ); ok {
+			for _, f := range fields {
+				b := rustBoundary(path, rustExtractorTypes[kind], src, f.name)
+				b.DataType = f.typ
+				idx.span(&b, m[0], m[1])
+				out = append(out, b)
+			}
+			continue
+		}
+		for _, n := range bindingNames(binding) {
+			b := rustBoundary(path, rustExtractorTypes[kind], src, n)
+			idx.span(&b, m[0], m[1])
+			out = append(out, b)
+		}
+	}
+
+	gets := rustHeaderGetRe.FindAllStringSubmatchIndex(content, -1)
+	for _, m := range rustHeaderMapRe.FindAllStringSubmatchIndex(content, -1) {
+		binding := content[m[2]:m[3]]
+		found := false
+		for _, g := range gets {
+			if content[g[2]:g[3]] != binding {
+				continue
+			}
+			found = true
+			b := rustBoundary(path, "http_header", framework, content[g[4]:g[5]])
+			idx.span(&b, g[0], g[1])
+			out = append(out, b)
+		}
+		if !found {
+			b := rustBoundary(path, "http_header", framework, binding)
+			b.Confidence = "low"
+			idx.span(&b, m[0], m[1])
+			out = append(out, b)
+		}
+	}
+
+	// req.headers().get works on any http::Request, so only name a framework
+	// the file actually imports.
+	src := rustFramework(content)
+	if src == "" {
+		src = "http"
+	}
+	for _, m := range rustReqHeaderRe.FindAllStringSubmatchIndex(content, -1) {
+		b := rustBoundary(path, "http_header", src, content[m[2]:m[3]])
+		idx.span(&b, m[0], m[1])
+		out = append(out, b)
+	}
+	return out
+}
+
+func rustBoundary(path, typ, source, variable string) Boundary {
+	return Boundary{
+		File: path, Type: typ,
+		Source: source, Variable: variable,
+		Validation: genValidation(typ),
+		FuzzInputs: genFuzz(typ),
+	}
+}
+
+// bindingNames returns the identifiers bound by an extractor pattern such as
+// "params", "Query(params)" or "Path((id, name))".
+func bindingNames(binding string) []string {
+	if i := strings.Index(binding, "("); i >= 0 {
+		binding = binding[i:]
+	}
+	var out []string
+	for _, f := range strings.FieldsFunc(binding, func(r rune) bool {
+		return r == '(' || r == ')' || r == ',' || r == ' ' || r == '\t' || r == '\n'
+	}) {
+		if f != "_" && f != "mut" {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+func lastPathSegment(typ string) string {
+	if i := strings.LastIndex(typ, "::"); i >= 0 {
+		return typ[i+2:]
+	}
+	return typ
+}
+
+// parseRustStructs returns the named-field structs declared in content, with
+// serde renames applied to field names.
+func parseRustStructs(content string) map[string][]rustField {
+	out := map[string][]rustField{}
+	for _, m := range rustStructRe.FindAllStringSubmatch(content, -1) {
+		var fields []rustField
+		rename, pending := "", ""
+		for _, line := range strings.Split(m[2], "\n") {
+			if pending != "" {
+				line, pending = pending+" "+strings.TrimSpace(line), ""
+			}
+			trimmed := strings.TrimSpace(line)
+			if strings.HasPrefix(trimmed, "#[") {
+				if r := rustRenameRe.FindStringSubmatch(trimmed); r != nil {
+					rename = r[1]
+				}
+				continue
+			}
+			f := rustFieldRe.FindStringSubmatch(line)
+			if f == nil {
+				continue
+			}
+			typ, ok := rustFieldType(f[2])
+			if !ok {
+				pending = line // the type continues on the next line
+				continue
+			}
+			name := f[1]
+			if rename != "" {
+				name, rename = rename, ""
+			}
+			fields = append(fields, rustField{name: name, typ: typ})
+		}
+		out[m[1]] = fields
+	}
+	return out
+}
+
+// rustFieldType returns the type at the start of s, which ends at a comma
+// outside any brackets, so that HashMap<String, String> stays whole. ok is
+// false while a bracket is still open at the end of s.
+func rustFieldType(s string) (typ string, ok bool) {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '<', '(', '[':
+			depth++
+		case '>':
+			// The > of -> in an fn type closes nothing.
+			if i == 0 || s[i-1] != '-' {
+				depth--
+			}
+		case ')', ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				return strings.TrimSpace(s[:i]), true
+			}
+		case '/':
+			if strings.HasPrefix(s[i:], "//") {
+				return strings.TrimSpace(s[:i]), depth <= 0
+			}
+		}
+	}
+	return strings.TrimSpace(s), depth <= 0
+}
+
+// rustResolver finds the struct a type path names, as seen from one file:
+// its own structs, its use declarations and the crate's modules.
+type rustResolver struct {
+	local map[string][]rustField
+	mods  map[string]map[string][]rustField // module path -> struct name -> fields
+	mod   string                            // the file's module path
+	uses  map[string]string                 // imported name -> full path
+	globs []string                          // modules imported with ::*
+}
+
+func newRustResolver(content, mod string, mods map[string]map[string][]rustField) *rustResolver {
+	r := &rustResolver{local: parseRustStructs(content), mods: mods, mod: mod, uses: map[string]string{}}
+	for _, m := range rustUseRe.FindAllStringSubmatch(content, -1) {
+		base, tail := r.absolute(strings.Split(m[1], "::")), m[2]
+		switch {
+		case tail == "::*":
+			r.globs = append(r.globs, base)
+		case strings.HasPrefix(tail, "::{"):
+			for _, item := range strings.Split(strings.Trim(tail, ":{}"), ",") {
+				name, alias, _ := strings.Cut(strings.TrimSpace(item), " as ")
+				name, alias = strings.TrimSpace(name), strings.TrimSpace(alias)
+				if name == "" || strings.Contains(name, "::") {
+					continue
+				}
+				full := base + "::" + name
+				if name == "self" {
+					full, name = base, lastPathSegment(base)
+				}
+				if alias == "" {
+					alias = name
+				}
+				r.uses[alias] = full
+			}
+		default:
+			alias := lastPathSegment(base)
+			if _, a, ok := strings.Cut(tail, " as "); ok {
+				alias = strings.TrimSpace(a)
+			}
+			r.uses[alias] = base
+		}
+	}
+	return r
+}
+
+// absolute turns the module path segs, as written in r's file, into a path
+// from the crate root.
+func (r *rustResolver) absolute(segs []string) string {
+	base := r.mod
+	switch first := segs[0]; {
+	case first == "crate":
+		base, segs = "crate", segs[1:]
+	case first == "self":
+		segs = segs[1:]
+	case first == "super":
+		for len(segs) > 0 && segs[0] == "super" {
+			if i := strings.LastIndex(base, "::"); i >= 0 {
+				base = base[:i]
+			}
+			segs = segs[1:]
+		}
+	case r.uses[first] != "":
+		base, segs = r.uses[first], segs[1:]
+	}
+	if len(segs) == 0 {
+		return base
+	}
+	return base + "::" + strings.Join(segs, "::")
+}
+
+// fields returns the fields of the struct typ names. A name that no path or
+// import resolves falls back to the one struct of that name in the crate, if
+// it is unique.
+func (r *rustResolver) fields(typ string) ([]rustField, bool) {
+	segs := strings.Split(typ, "::")
+	name := segs[len(segs)-1]
+	if len(segs) == 1 {
+		if f, ok := r.local[name]; ok {
+			return f, true
+		}
+		if full, ok := r.uses[name]; ok {
+			if f, ok := r.lookup(full); ok {
+				return f, true
+			}
+		}
+		for _, g := range r.globs {
+			if f, ok := r.lookup(g + "::" + name); ok {
+				return f, true
+			}
+		}
+	} else if f, ok := r.lookup(r.absolute(segs[:len(segs)-1]) + "::" + name); ok {
+		return f, true
+	}
+	var found []rustField
+	n := 0
+	for _, structs := range r.mods {
+		if f, ok := structs[name]; ok {
+			found, n = f, n+1
+		}
+	}
+	return found, n == 1
+}
+
+// lookup returns the fields of the struct at the full path.
+func (r *rustResolver) lookup(full string) ([]rustField, bool) {
+	i := strings.LastIndex(full, "::")
+	if i < 0 {
+		return nil, false
+	}
+	mod, name := full[:i], full[i+2:]
+	if mod == r.mod {
+		if f, ok := r.local[name]; ok {
+			return f, true
+		}
+	}
+	f, ok := r.mods[mod][name]
+	return f, ok
+}
+
+var (
+	crateMu    sync.Mutex
+	crateCache = map[string]map[string]map[string][]rustField{}
+)
+
+// crateStructs indexes the structs of the Cargo crate that contains path by
+// module path, such as "crate::api::models", and returns the module path of
+// path itself. The index is empty when path is not on disk or not inside a
+// crate.
+func crateStructs(path string) (map[string]map[string][]rustField, string) {
+	root := crateRoot(path)
+	if root == "" {
+		return nil, "crate"
+	}
+	crateMu.Lock()
+	defer crateMu.Unlock()
+	idx, ok := crateCache[root]
+	if !ok {
+		idx = map[string]map[string][]rustField{}
+		filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+			if err != nil {
+				return nil
+			}
+			if info.IsDir() && info.Name() == "target" {
+				return filepath.SkipDir
+			}
+			if info.IsDir() || filepath.Ext(p) != ".rs" {
+				return nil
+			}
+			data, err := os.ReadFile(p)
+			if err != nil {
+				return nil
+			}
+			idx[rustModule(root, p)] = parseRustStructs(string(data))
+			return nil
+		})
+		crateCache[root] = idx
+	}
+	abs, _ := filepath.Abs(path)
+	return idx, rustModule(root, abs)
+}
+
+// rustModule returns the module path of the source file p in the crate at
+// root, following the src/a/b.rs and src/a/mod.rs layouts.
+func rustModule(root, p string) string {
+	rel, err := filepath.Rel(filepath.Join(root, "src"), p)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "crate"
+	}
+	segs := strings.Split(filepath.ToSlash(strings.TrimSuffix(rel, ".rs")), "/")
+	switch last := segs[len(segs)-1]; {
+	case last == "mod":
+		segs = segs[:len(segs)-1]
+	case len(segs) == 1 && (last == "lib" || last == "main"):
+		segs = nil
+	}
+	return strings.Join(append([]string{"crate"}, segs...), "::")
+}
+
+func crateRoot(path string) string {
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	dir, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return ""
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "Cargo.toml")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestScanRustAxumExtractors(t *testing.T) {
+	code := "#[derive(Deserialize)]\n" +
+		"pub struct Search {\n" +
+		"    pub q: String,\n" +
+		"    #[serde(rename = \"pageSize\")]\n" +
+		"    pub page_size: Option<u32>,\n" +
+		"}\n" +
+		"async fn search(Query(params): Query<Search>, Path((org, id)): Path<(String, u64)>, headers: HeaderMap) {\n" +
+		"    let tok = headers.get(\"authorization\");\n" +
+		"    let db = std::env::var(\"DATABASE_URL\").unwrap();\n" +
+		"}\n"
+	bs := ScanContent(code, "src/handlers.rs", ".rs")
+	if len(bs) != 6 {
+		t.Fatalf("want 6 boundaries, got %d: %+v", len(bs), bs)
+	}
+	assertBoundary(t, bs[0], "q", "http_query", "axum")
+	assertBoundary(t, bs[1], "pageSize", "http_query", "axum")
+	assertBoundary(t, bs[2], "org", "http_path", "axum")
+	assertBoundary(t, bs[3], "id", "http_path", "axum")
+	assertBoundary(t, bs[4], "authorization", "http_header", "axum")
+	assertBoundary(t, bs[5], "DATABASE_URL", "env_var", "Env Var")
+	if bs[0].Line != 7 || bs[5].Line != 9 {
+		t.Errorf("lines: want 7 and 9, got %d and %d", bs[0].Line, bs[5].Line)
+	}
+}
+
+func TestScanRustActix(t *testing.T) {
+	code := "use actix_web::{web, HttpRequest};\n" +
+		"async fn create(body: web::Json<HashMap<String, String>>, req: HttpRequest) -> impl Responder {\n" +
+		"    let ua = req.headers().get(\"User-Agent\");\n" +
+		"    let args: Vec<String> = std::env::args().collect();\n" +
+		"}\n"
+	bs := ScanContent(code, "src/main.rs", ".rs")
+	if len(bs) != 3 {
+		t.Fatalf("want 3 boundaries, got %d: %+v", len(bs), bs)
+	}
+	assertBoundary(t, bs[0], "body", "http_body", "actix-web")
+	assertBoundary(t, bs[1], "User-Agent", "http_header", "actix-web")
+	assertBoundary(t, bs[2], "args", "env_var", "CLI Args")
+}
+
+func TestScanRustRequestHeaderFramework(t *testing.T) {
+	code := "fn auth(req: &http::Request<Body>) {\n" +
+		"    let tok = req.headers().get(\"Authorization\");\n" +
+		"}\n"
+	bs := ScanContent(code, "src/auth.rs", ".rs")
+	if len(bs) != 1 {
+		t.Fatalf("want 1 boundary, got %d: %+v", len(bs), bs)
+	}
+	assertBoundary(t, bs[0], "Authorization", "http_header", "http")
+}
+
+func TestScanRustCrateStructExpansion(t *testing.T) {
+	root := t.TempDir()
+	mustWrite(t, filepath.Join(root, "Cargo.toml"), "[package]\nname = \"svc\"\n")
+	mustWrite(t, filepath.Join(root, "src", "models.rs"),
+		"pub struct NewUser {\n    pub name: String,\n    pub(crate) age: u8,\n}\n")
+	handler := filepath.Join(root, "src", "routes.rs")
+	mustWrite(t, handler, "use crate::models;\n"+
+		"async fn create(Json(u): Json<models::NewUser>) {}\n")
+
+	bs := ScanFile(handler, ".rs")
+	if len(bs) != 2 {
+		t.Fatalf("want 2 boundaries, got %d: %+v", len(bs), bs)
+	}
+	assertBoundary(t, bs[0], "name", "http_body", "axum")
+	assertBoundary(t, bs[1], "age", "http_body", "axum")
+}
+
+func TestScanRustFieldTypesWithCommas(t *testing.T) {
+	code := "struct Upload {\n" +
+		"    pub meta: HashMap<String, String>,\n" +
+		"    pub pairs: Vec<(u8,\n        u16)>,\n" +
+		"    pub name: String, // shown to others, so keep it short\n" +
+		"}\n" +
+		"async fn upload(Json(u): Json<Upload>) {}\n"
+	bs := ScanContent(code, "src/upload.rs", ".rs")
+	if len(bs) != 3 {
+		t.Fatalf("want 3 boundaries, got %d: %+v", len(bs), bs)
+	}
+	for i, want := range []string{"HashMap<String, String>", "Vec<(u8, u16)>", "String"} {
+		if bs[i].DataType != want {
+			t.Errorf("field %d: type %q, want %q", i, bs[i].DataType, want)
+		}
+	}
+}
+
+func TestScanRustSameNamedStructs(t *testing.T) {
+	root := t.TempDir()
+	mustWrite(t, filepath.Join(root, "Cargo.toml"), "[package]\nname = \"svc\"\n")
+	mustWrite(t, filepath.Join(root, "src", "admin", "mod.rs"),
+		"pub struct Filter {\n    pub role: String,\n}\n")
+	mustWrite(t, filepath.Join(root, "src", "users.rs"),
+		"pub struct Filter {\n    pub email: String,\n}\n")
+	handler := filepath.Join(root, "src", "routes.rs")
+	mustWrite(t, handler, "use crate::users::Filter;\n"+
+		"use crate::admin;\n"+
+		"async fn list(Query(f): Query<Filter>) {}\n"+
+		"async fn audit(Query(f): Query<admin::Filter>) {}\n")
+
+	bs := ScanFile(handler, ".rs")
+	if len(bs) != 2 {
+		t.Fatalf("want 2 boundaries, got %d: %+v", len(bs), bs)
+	}
+	assertBoundary(t, bs[0], "email", "http_query", "axum")
+	assertBoundary(t, bs[1], "role", "http_query", "axum")
+}
+
+func mustWrite(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// sinkSanitizer is the remediation for values reaching one kind of sink: a
+// line for reports and a Go snippet for generated rules. In code, %[1]s is
+// the value, %[2]q the field name, %[3]s a local name derived from it and
+// %[4]s a column name for example queries.
+type sinkSanitizer struct {
+	advice string
+	code   string
+}
+
+// sinkSanitizers is keyed by Boundary.Sink.
+var sinkSanitizers = map[string]sinkSanitizer{
+	"html": {
+		advice: "escape for the HTML context it is written into (html/template, html.EscapeString)",
+		code: `// Render %[1]s through html/template, which escapes for each context
+// (element, attribute, URL, script); when writing HTML by hand, escape it.
+%[3]sHTML := html.EscapeString(%[1]s)`,
+	},
+	"sql": {
+		advice: "pass as a bound query parameter, never concatenated into the SQL text",
+		code: `// Pass %[1]s as a query argument so the driver sends it apart from the
+// SQL text; never concatenate or fmt.Sprintf it into the query. Use ? for
+// MySQL and SQLite, $1 for PostgreSQL.
+rows, err := db.QueryContext(ctx, "SELECT id FROM items WHERE %[4]s = ?", %[1]s)`,
+	},
+	"path": {
+		advice: "clean as a rooted path, join it to the allowed root and check the result stays inside (filepath.Clean, filepath.Rel)",
+		code: `// Clean %[1]s as a rooted path so ".." cannot climb out of root, the
+// directory it must stay in, then check the joined path is still inside.
+%[3]sPath := filepath.Join(root, filepath.Clean("/"+%[1]s))
+if rel, err := filepath.Rel(root, %[3]sPath); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+	return &ValidationError{Field: %[2]q, Rule: "path", Limit: root, Got: %[1]s}
+}`,
+	},
+	"redirect": {
+		advice: "parse the URL and allow only local paths or allowlisted hosts (url.Parse)",
+		code: `// Allow only local paths, or http(s) URLs whose host is in
+// allowedRedirectHosts. "//host", "/\host" and "javascript:" URLs fail.
+if u, err := url.Parse(%[1]s); err != nil || u.User != nil || strings.Contains(%[1]s, "\\") ||
+	(u.Host == "" && (u.Scheme != "" || !strings.HasPrefix(u.Path, "/"))) ||
+	(u.Host != "" && (u.Scheme != "https" && u.Scheme != "http" || !allowedRedirectHosts[u.Hostname()])) {
+	return &ValidationError{Field: %[2]q, Rule: "redirect", Got: %[1]s}
+}`,
+	},
+	"shell": {
+		advice: "run the program directly with the value as its own argument, never through a shell (exec.Command)",
+		code: `// Run the program directly with %[1]s as one argument; never pass it to
+// sh -c or build a command line from it. "--" ends option parsing, so a
+// leading "-" is not taken as a flag.
+cmd := exec.CommandContext(ctx, "program", "--", %[1]s)`,
+	},
+}
+
+// sanitizerRule returns the rule that makes the value v, reported as field,
+// safe for e's Sink. Snippets refer to names the caller supplies: db and ctx
+// for SQL, root for paths, allowedRedirectHosts for redirects and ctx for
+// commands.
+func sanitizerRule(e BoundaryEntry, v, field string) (ValidationRule, bool) {
+	s, ok := sinkSanitizers[e.Sink]
+	if !ok {
+		return ValidationRule{}, false
+	}
+	local := lowerFirst(camelName(field))
+	if local == "" {
+		local = "value"
+	}
+	return ValidationRule{
+		ParamName: field,
+		RuleType:  "sanitize",
+		GoCode:    fmt.Sprintf(s.code, v, field, local, columnName(field)),
+	}, true
+}
+
+// columnName returns field as a snake_case SQL column name: "userId" and
+// "X-User-ID" become "user_id" and "x_user_id".
+func columnName(field string) string {
+	parts := strings.FieldsFunc(field, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, p := range parts {
+		parts[i] = strings.ToLower(splitWords(p))
+	}
+	if len(parts) == 0 {
+		return "value"
+	}
+	return strings.Join(parts, "_")
+}
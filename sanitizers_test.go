@@ -0,0 +1,45 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitizerRules(t *testing.T) {
+	for sink, want := range map[string]string{
+		"html":     "redirectUrlHTML := html.EscapeString(redirect_url)",
+		"sql":      `db.QueryContext(ctx, "SELECT id FROM items WHERE redirect_url = ?", redirect_url)`,
+		"path":     `redirectUrlPath := filepath.Join(root, filepath.Clean("/"+redirect_url))`,
+		"redirect": "!allowedRedirectHosts[u.Hostname()]",
+		"shell":    `exec.CommandContext(ctx, "program", "--", redirect_url)`,
+	} {
+		rules := GenerateRules([]BoundaryEntry{{ParamName: "redirect_url", DataType: "string", MaxLength: 2048, Format: "none", Sink: sink}})
+		last := rules[len(rules)-1]
+		if last.RuleType != "sanitize" || !strings.Contains(last.GoCode, want) {
+			t.Errorf("%s: want a sanitize rule containing %q last, got %+v", sink, want, rules)
+		}
+	}
+	for _, r := range GenerateRules([]BoundaryEntry{{ParamName: "q", DataType: "string", Sink: "ldap"}}) {
+		if r.RuleType == "sanitize" {
+			t.Errorf("unknown sink got a sanitizer: %+v", r)
+		}
+	}
+}
+
+func TestEntriesFromBoundariesSink(t *testing.T) {
+	es := EntriesFromBoundaries([]Boundary{
+		{Function: "h", Variable: "q"},
+		{Function: "h", Variable: "q", Sink: "sql"},
+	})
+	if len(es) != 1 || es[0].Sink != "sql" {
+		t.Errorf("want one entry keeping the sink, got %+v", es)
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	for in, want := range map[string]string{"redirect_url": "redirect_url", "userId": "user_id", "X-User-ID": "x_user_id", "$": "value"} {
+		if got := columnName(in); got != want {
+			t.Errorf("columnName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
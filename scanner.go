@@ -1,44 +1,45 @@
 package main
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"os"
-	"regexp"
-	"strings"
+	"path/filepath"
+	"sort"
 )
 
 type Boundary struct {
-	File       string   `json:"file"`
-	Line       int      `json:"line"`
-	Type       string   `json:"type"`
-	Source     string   `json:"source"`
-	Variable   string   `json:"variable"`
+	File      string `json:"file"`
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	EndLine   int    `json:"end_line"`
+	EndColumn int    `json:"end_column"` // exclusive, as in SARIF and LSP ranges
+	Function  string `json:"function,omitempty"`
+	Type      string `json:"type"`
+	Source    string `json:"source"`
+	Variable  string `json:"variable"`
+	DataType  string `json:"data_type,omitempty"`
+	// Field is the struct field the value is decoded into, as "Type.Field",
+	// for request bodies the scanner resolved to a struct.
+	Field string `json:"field,omitempty"`
+	// Optional is set when the input may be left out, as for a request
+	// struct field tagged omitempty. Optional types are recognised from
+	// DataType instead.
+	Optional   bool     `json:"optional,omitempty"`
+	Sink       string   `json:"sink,omitempty"` // dangerous use reached: "sql", "shell", "path", "redirect" or "html"
+	Guarded    bool     `json:"guarded"`
+	Severity   string   `json:"severity"`
+	Confidence string   `json:"confidence"` // "high" for AST matches, "medium" for regex, "low" for heuristics
 	Validation []string `json:"validation_rules"`
 	FuzzInputs []string `json:"fuzz_inputs"`
-}
-
-type rule struct {
-	exts   []string
-	typ    string
-	source string
-	re     *regexp.Regexp
-	idx    int
-}
-
-var rules = []rule{
-	{[]string{".go"}, "http_query", "URL Query",
-		regexp.MustCompile(`(?:URL\.Query\(\)\.Get|FormValue)\("([^"]+)"\)`), 1},
-	{[]string{".go"}, "env_var", "Env Var",
-		regexp.MustCompile(`os\.Getenv\("([^"]+)"\)`), 1},
-	{[]string{".go"}, "http_header", "HTTP Header",
-		regexp.MustCompile(`Header\.Get\("([^"]+)"\)`), 1},
-	{[]string{".py"}, "http_query", "Flask/Django",
-		regexp.MustCompile(`request\.(?:args|form|json)(?:\.get\(|\.?\[)['"]([\w]+)`), 1},
-	{[]string{".py"}, "env_var", "Env Var",
-		regexp.MustCompile(`os\.(?:environ\.get|getenv)\(['"]([^'"]+)['"]\)`), 1},
-	{[]string{".js", ".ts"}, "http_query", "Express",
-		regexp.MustCompile(`req\.(query|params|body)\.(\w+)`), 2},
-	{[]string{".js", ".ts"}, "env_var", "Env Var",
-		regexp.MustCompile(`process\.env\.(\w+)`), 1},
+	// Fingerprint identifies the boundary across scans. It does not depend
+	// on line numbers, so it survives unrelated edits to the file.
+	Fingerprint string `json:"fingerprint"`
+	// Snippet is the trimmed source around the boundary, starting at line
+	// SnippetStart.
+	Snippet      string `json:"snippet,omitempty"`
+	SnippetStart int    `json:"snippet_start,omitempty"`
 }
 
 func ScanFile(path, ext string) []Boundary {
@@ -49,35 +50,63 @@ func ScanFile(path, ext string) []Boundary {
 	return ScanContent(string(data), path, ext)
 }
 
+// ScanContent runs every detector registered for ext over content and returns
+// the boundaries found, ordered by position and annotated with their
+// enclosing function and a code snippet.
 func ScanContent(content, path, ext string) []Boundary {
 	var out []Boundary
-	for i, line := range strings.Split(content, "\n") {
-		for _, r := range rules {
-			if !hasExt(r.exts, ext) {
-				continue
-			}
-			m := r.re.FindStringSubmatch(line)
-			if m == nil || r.idx >= len(m) {
-				continue
-			}
-			out = append(out, Boundary{
-				File: path, Line: i + 1, Type: r.typ,
-				Source: r.source, Variable: m[r.idx],
-				Validation: genValidation(r.typ),
-				FuzzInputs: genFuzz(r.typ),
-			})
-		}
+	for _, d := range detectorsFor(ext) {
+		out = append(out, d.Detect(content, path)...)
 	}
+	annotate(out, content, ext)
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Line != out[j].Line {
+			return out[i].Line < out[j].Line
+		}
+		return out[i].Column < out[j].Column
+	})
+	fingerprint(out)
 	return out
 }
 
-func hasExt(exts []string, ext string) bool {
-	for _, e := range exts {
-		if e == ext {
-			return true
+// fingerprint sets the Fingerprint of each boundary in bs, which must all
+// come from the same file in source order. Identical boundaries within a
+// function are told apart by their order of appearance.
+func fingerprint(bs []Boundary) {
+	seen := map[string]int{}
+	for i := range bs {
+		b := &bs[i]
+		key := fmt.Sprintf("%s\x00%s\x00%s\x00%s\x00%s",
+			filepath.ToSlash(filepath.Clean(b.File)), b.Type, b.Source, b.Variable, b.Function)
+		sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d", key, seen[key])))
+		seen[key]++
+		b.Fingerprint = hex.EncodeToString(sum[:])
+	}
+}
+
+// lineIndex maps byte offsets in a file to line and column numbers.
+type lineIndex []int
+
+func newLineIndex(content string) lineIndex {
+	idx := lineIndex{0}
+	for i := 0; i < len(content); i++ {
+		if content[i] == '\n' {
+			idx = append(idx, i+1)
 		}
 	}
-	return false
+	return idx
+}
+
+// pos returns the 1-based line and byte column of offset off.
+func (idx lineIndex) pos(off int) (line, col int) {
+	line = sort.Search(len(idx), func(i int) bool { return idx[i] > off })
+	return line, off - idx[line-1] + 1
+}
+
+// span sets the start and end position of b from the byte range [start, end).
+func (idx lineIndex) span(b *Boundary, start, end int) {
+	b.Line, b.Column = idx.pos(start)
+	b.EndLine, b.EndColumn = idx.pos(end)
 }
 
 func genValidation(typ string) []string {
@@ -85,10 +114,18 @@ func genValidation(typ string) []string {
 	switch typ {
 	case "http_query":
 		return append(base, "sanitize HTML entities", "validate against allowlist")
+	case "http_path":
+		return append(base, "reject path traversal sequences", "validate against route pattern")
+	case "http_body":
+		return append(base, "enforce request body size limit", "reject unknown fields")
 	case "http_header":
 		return append(base, "reject CRLF characters", "validate header format")
+	case "http_cookie":
+		return append(base, "verify signature or integrity", "reject CRLF and ';' characters")
 	case "env_var":
 		return append(base, "provide default value", "validate format on startup")
+	case "post_message":
+		return append(base, "verify event.origin against allowlist", "validate event.data shape")
 	}
 	return base
 }
@@ -98,8 +135,16 @@ func genFuzz(typ string) []string {
 	switch typ {
 	case "http_query":
 		return append(base, `"%0d%0aInjected"`, `"{{7*7}}"`, `"../../../etc/passwd"`)
+	case "http_path":
+		return append(base, `"..%2f..%2fetc%2fpasswd"`, `"-1"`, `"%00"`)
+	case "http_body":
+		return append(base, `"{\"__proto__\":{\"admin\":true}}"`, `"[]"`, `"null"`)
 	case "http_header":
 		return append(base, `"\r\nX-Injected: true"`, `"bytes(0x00-0xff)"`)
+	case "http_cookie":
+		return append(base, `"a=b; admin=true"`, `"%0d%0aSet-Cookie: session=x"`, `"eyJhbGciOiJub25lIn0."`)
+	case "post_message":
+		return append(base, `"{\"type\":\"__proto__\"}"`, `"origin: https://evil.example"`, `"javascript:alert(1)"`)
 	case "env_var":
 		return append(base, `"$(whoami)"`, `"; rm -rf /"`, `"\x00NULL"`)
 	}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+)
+
+// jsonSchemaDialect is the JSON Schema draft used by both generators;
+// OpenAPI 3.1 schemas share it.
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema is the subset of JSON Schema that BoundaryEntry constraints map
+// onto.
+type JSONSchema struct {
+	Schema     string                 `json:"$schema,omitempty"`
+	Type       string                 `json:"type,omitempty"`
+	MinLength  *int                   `json:"minLength,omitempty"`
+	MaxLength  *int                   `json:"maxLength,omitempty"`
+	Minimum    any                    `json:"minimum,omitempty"` // int64, uint64 or float64
+	Maximum    any                    `json:"maximum,omitempty"`
+	MinItems   *int                   `json:"minItems,omitempty"`
+	MaxItems   *int                   `json:"maxItems,omitempty"`
+	Unique     bool                   `json:"uniqueItems,omitempty"`
+	Items      *JSONSchema            `json:"items,omitempty"`
+	Enum       []string               `json:"enum,omitempty"`
+	Pattern    string                 `json:"pattern,omitempty"`
+	Format     string                 `json:"format,omitempty"`
+	Properties map[string]*JSONSchema `json:"properties,omitempty"`
+	Required   []string               `json:"required,omitempty"`
+	Defs       map[string]*JSONSchema `json:"$defs,omitempty"`
+}
+
+// EntrySchema returns the JSON Schema for a single entry's value. Lists and
+// objects nest the schemas of their items and fields.
+func EntrySchema(e BoundaryEntry) *JSONSchema {
+	s := &JSONSchema{Type: "string", Enum: e.EnumValues, Pattern: e.RegexPattern}
+	switch e.DataType {
+	case "int":
+		s.Type = "integer"
+		if e.MinValue != 0 || e.MaxValue != 0 {
+			s.Minimum, s.Maximum = e.MinValue, e.MaxValue
+		}
+	case "uint":
+		s.Type, s.Minimum = "integer", uint64(max(e.MinValue, 0))
+		if e.MaxValue > 0 {
+			s.Maximum = uint64(e.MaxValue)
+		}
+	case "float64":
+		// JSON has no NaN or Inf, so "number" already rules them out.
+		s.Type = "number"
+		if e.MinFloat != 0 || e.MaxFloat != 0 {
+			s.Minimum, s.Maximum = e.MinFloat, e.MaxFloat
+		}
+	case "bool":
+		s.Type = "boolean"
+	case "list":
+		s.Type, s.Unique = "array", e.UniqueItems
+		if e.MinItems > 0 {
+			s.MinItems = &e.MinItems
+		}
+		if e.MaxItems > 0 {
+			s.MaxItems = &e.MaxItems
+		}
+		if e.Items != nil {
+			s.Items = EntrySchema(*e.Items)
+		}
+		return s
+	case "object":
+		s.Type, s.Properties = "object", map[string]*JSONSchema{}
+		for _, f := range e.Fields {
+			s.Properties[f.ParamName] = EntrySchema(f)
+			if entryRequired(f) {
+				s.Required = append(s.Required, f.ParamName)
+			}
+		}
+		sort.Strings(s.Required)
+		return s
+	default:
+		if e.MinLength > 0 {
+			s.MinLength = &e.MinLength
+		}
+		if e.MaxLength > 0 {
+			s.MaxLength = &e.MaxLength
+		}
+	}
+	if f := entryFormat(e); f != "" {
+		s.Format = formats[f].jsonSchema
+		// Keep the pattern where the format name alone is not enough: no
+		// standard name, or a scheme allowlist.
+		if s.Pattern == "" && (s.Format == "" || f == "url") {
+			s.Pattern = formatPattern(f, entrySchemes(e))
+		}
+	}
+	return s
+}
+
+// entryRequired reports whether an entry must be present: only a minimum
+// length or item count says so.
+func entryRequired(e BoundaryEntry) bool {
+	return e.MinLength > 0 || e.MinItems > 0
+}
+
+// GenerateJSONSchema returns a JSON Schema document with one object
+// definition per handler, named <Handler>Request, whose properties are the
+// handler's entries. Entries without a Handler go into "Request".
+func GenerateJSONSchema(entries []BoundaryEntry) *JSONSchema {
+	doc := &JSONSchema{Schema: jsonSchemaDialect, Defs: map[string]*JSONSchema{}}
+	for _, e := range mergeByName(entries) {
+		name := camelName(e.Handler) + "Request"
+		obj := doc.Defs[name]
+		if obj == nil {
+			obj = &JSONSchema{Type: "object", Properties: map[string]*JSONSchema{}}
+			doc.Defs[name] = obj
+		}
+		obj.Properties[e.ParamName] = EntrySchema(e)
+		if entryRequired(e) {
+			obj.Required = append(obj.Required, e.ParamName)
+		}
+	}
+	for _, obj := range doc.Defs {
+		sort.Strings(obj.Required)
+	}
+	return doc
+}
+
+// mergeByName merges the entries of a handler that share a ParamName, such
+// as one env var read in several files, so that each becomes one property.
+// The merged entry keeps the first entry's type and the tighter limits.
+func mergeByName(entries []BoundaryEntry) []BoundaryEntry {
+	var out []BoundaryEntry
+	seen := map[[2]string]int{}
+	for _, e := range entries {
+		key := [2]string{camelName(e.Handler), e.ParamName}
+		if i, ok := seen[key]; ok {
+			out[i] = tighterEntry(out[i], e)
+			continue
+		}
+		seen[key] = len(out)
+		out = append(out, e)
+	}
+	return out
+}
+
+// tighterEntry returns a with b's limits applied where they are stricter,
+// and b's pattern, enum, format and sink where a has none.
+func tighterEntry(a, b BoundaryEntry) BoundaryEntry {
+	a.MinLength, a.MaxLength = max(a.MinLength, b.MinLength), minLimit(a.MaxLength, b.MaxLength)
+	a.MinItems, a.MaxItems = max(a.MinItems, b.MinItems), minLimit(a.MaxItems, b.MaxItems)
+	if a.DataType == b.DataType && (b.MinValue != 0 || b.MaxValue != 0) {
+		if a.MinValue == 0 && a.MaxValue == 0 {
+			a.MinValue, a.MaxValue = b.MinValue, b.MaxValue
+		} else {
+			a.MinValue, a.MaxValue = max(a.MinValue, b.MinValue), min(a.MaxValue, b.MaxValue)
+		}
+	}
+	if a.DataType == b.DataType && (b.MinFloat != 0 || b.MaxFloat != 0) {
+		if a.MinFloat == 0 && a.MaxFloat == 0 {
+			a.MinFloat, a.MaxFloat = b.MinFloat, b.MaxFloat
+		} else {
+			a.MinFloat, a.MaxFloat = max(a.MinFloat, b.MinFloat), min(a.MaxFloat, b.MaxFloat)
+		}
+	}
+	if a.RegexPattern == "" {
+		a.RegexPattern = b.RegexPattern
+	}
+	if a.EnumValues == nil {
+		a.EnumValues = b.EnumValues
+	}
+	if a.Format == "" {
+		a.Format = b.Format
+	}
+	if a.Sink == "" {
+		a.Sink = b.Sink
+	}
+	return a
+}
+
+// minLimit returns the smaller of two limits where 0 means no limit.
+func minLimit(a, b int) int {
+	if a == 0 || b != 0 && b < a {
+		return b
+	}
+	return a
+}
+
+// openAPIIn maps boundary types to OpenAPI parameter locations. Bodies are
+// described as request body schemas instead, and other types are not HTTP
+// inputs.
+var openAPIIn = map[string]string{
+	"http_query":  "query",
+	"http_header": "header",
+	"http_path":   "path",
+	"http_cookie": "cookie",
+}
+
+// OpenAPIParameter is an OpenAPI 3.1 Parameter Object.
+type OpenAPIParameter struct {
+	Name     string      `json:"name"`
+	In       string      `json:"in"`
+	Required bool        `json:"required,omitempty"`
+	Schema   *JSONSchema `json:"schema"`
+}
+
+// OpenAPIFragment is the part of an OpenAPI 3.1 document that
+// GenerateOpenAPI fills in, ready to merge into an existing spec.
+type OpenAPIFragment struct {
+	OpenAPI           string `json:"openapi"`
+	JSONSchemaDialect string `json:"jsonSchemaDialect"`
+	Components        struct {
+		Parameters map[string]*OpenAPIParameter `json:"parameters,omitempty"`
+		Schemas    map[string]*JSONSchema       `json:"schemas,omitempty"`
+	} `json:"components"`
+}
+
+// GenerateOpenAPI returns an OpenAPI 3.1 fragment declaring a reusable
+// parameter, named <Handler><Param>, for every query, header, path and
+// cookie entry, located by its Source. http_body entries become properties
+// of a <Handler>Body schema; entries of other types are skipped.
+func GenerateOpenAPI(entries []BoundaryEntry) *OpenAPIFragment {
+	doc := &OpenAPIFragment{OpenAPI: "3.1.0", JSONSchemaDialect: jsonSchemaDialect}
+	doc.Components.Parameters = map[string]*OpenAPIParameter{}
+	doc.Components.Schemas = map[string]*JSONSchema{}
+	for _, e := range mergeByName(entries) {
+		if e.Source == "http_body" {
+			name := camelName(e.Handler) + "Body"
+			obj := doc.Components.Schemas[name]
+			if obj == nil {
+				obj = &JSONSchema{Type: "object", Properties: map[string]*JSONSchema{}}
+				doc.Components.Schemas[name] = obj
+			}
+			obj.Properties[e.ParamName] = EntrySchema(e)
+			if entryRequired(e) {
+				obj.Required = append(obj.Required, e.ParamName)
+			}
+			continue
+		}
+		in, ok := openAPIIn[e.Source]
+		if !ok {
+			continue
+		}
+		doc.Components.Parameters[camelName(e.Handler)+camelName(e.ParamName)] = &OpenAPIParameter{
+			Name: e.ParamName,
+			In:   in,
+			// OpenAPI requires path parameters to be marked required.
+			Required: in == "path" || entryRequired(e),
+			Schema:   EntrySchema(e),
+		}
+	}
+	for _, obj := range doc.Components.Schemas {
+		sort.Strings(obj.Required)
+	}
+	return doc
+}
+
+// writeJSON writes v as indented JSON.
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestEntrySchema(t *testing.T) {
+	s := EntrySchema(BoundaryEntry{DataType: "string", MinLength: 1, MaxLength: 64, RegexPattern: "^[a-z]+$"})
+	if s.Type != "string" || *s.MinLength != 1 || *s.MaxLength != 64 || s.Pattern != "^[a-z]+$" || s.Minimum != nil {
+		t.Errorf("bad string schema: %+v", s)
+	}
+	s = EntrySchema(BoundaryEntry{DataType: "int", MinValue: 0, MaxValue: 150})
+	if s.Type != "integer" || s.Minimum != int64(0) || s.Maximum != int64(150) || s.MaxLength != nil {
+		t.Errorf("bad integer schema: %+v", s)
+	}
+	s = EntrySchema(BoundaryEntry{DataType: "enum", EnumValues: []string{"a", "b"}})
+	if s.Type != "string" || len(s.Enum) != 2 {
+		t.Errorf("bad enum schema: %+v", s)
+	}
+	s = EntrySchema(BoundaryEntry{DataType: "float64", MinFloat: 0, MaxFloat: 1.5})
+	if s.Type != "number" || s.Minimum != 0.0 || s.Maximum != 1.5 {
+		t.Errorf("bad number schema: %+v", s)
+	}
+}
+
+func TestEntrySchemaNested(t *testing.T) {
+	s := EntrySchema(BoundaryEntry{DataType: "object", Fields: []BoundaryEntry{
+		{ParamName: "tags", DataType: "list", MinItems: 1, UniqueItems: true, Items: &BoundaryEntry{DataType: "string", MaxLength: 8}},
+		{ParamName: "admin", DataType: "bool"},
+	}})
+	if s.Type != "object" || len(s.Required) != 1 || s.Required[0] != "tags" {
+		t.Fatalf("bad object schema: %+v", s)
+	}
+	tags := s.Properties["tags"]
+	if tags.Type != "array" || *tags.MinItems != 1 || !tags.Unique || tags.Items.Type != "string" || *tags.Items.MaxLength != 8 {
+		t.Errorf("bad array schema: %+v", tags)
+	}
+	if s.Properties["admin"].Type != "boolean" {
+		t.Errorf("bad boolean schema: %+v", s.Properties["admin"])
+	}
+}
+
+func TestGenerateJSONSchema(t *testing.T) {
+	doc := GenerateJSONSchema([]BoundaryEntry{
+		{ParamName: "name", DataType: "string", MinLength: 1, MaxLength: 64, Handler: "create_user"},
+		{ParamName: "age", DataType: "int", MinValue: 0, MaxValue: 150, Handler: "create_user"},
+		{ParamName: "PORT", DataType: "int"},
+	})
+	var buf strings.Builder
+	if err := writeJSON(&buf, doc); err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal([]byte(buf.String()), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["$schema"] != jsonSchemaDialect {
+		t.Errorf("missing $schema: %v", got["$schema"])
+	}
+	obj := doc.Defs["CreateUserRequest"]
+	if obj == nil || len(obj.Properties) != 2 || strings.Join(obj.Required, ",") != "name" {
+		t.Fatalf("bad CreateUserRequest: %+v", obj)
+	}
+	if doc.Defs["Request"] == nil || doc.Defs["Request"].Properties["PORT"] == nil {
+		t.Error("entries without a handler should go into Request")
+	}
+	if !strings.Contains(buf.String(), `"maximum": 150`) || !strings.Contains(buf.String(), `"minimum": 0`) {
+		t.Errorf("zero bounds must be kept:\n%s", buf.String())
+	}
+}
+
+func TestGenerateJSONSchemaMergesDuplicates(t *testing.T) {
+	es := EntriesFromBoundaries([]Boundary{
+		{File: "a.go", Type: "env_var", Variable: "HOST"},
+		{File: "b.go", Type: "env_var", Variable: "HOST"},
+	})
+	es[1].MaxLength = 253
+	obj := GenerateJSONSchema(es).Defs["Request"]
+	if obj == nil || strings.Join(obj.Required, ",") != "HOST" {
+		t.Fatalf("want HOST required once, got %+v", obj)
+	}
+	if p := obj.Properties["HOST"]; p.MaxLength == nil || *p.MaxLength != 253 {
+		t.Errorf("want the tighter max length kept, got %+v", p)
+	}
+	body := GenerateOpenAPI([]BoundaryEntry{
+		{ParamName: "q", Source: "http_body", DataType: "string", MinLength: 1},
+		{ParamName: "q", Source: "http_body", DataType: "string", MinLength: 1},
+	}).Components.Schemas["Body"]
+	if body == nil || len(body.Required) != 1 {
+		t.Errorf("want q required once, got %+v", body)
+	}
+}
+
+func TestGenerateOpenAPI(t *testing.T) {
+	doc := GenerateOpenAPI([]BoundaryEntry{
+		{ParamName: "q", Source: "http_query", DataType: "string", MaxLength: 100, Handler: "search"},
+		{ParamName: "X-Request-Id", Source: "http_header", DataType: "string", MinLength: 1},
+		{ParamName: "id", Source: "http_path", DataType: "int", Handler: "get_user"},
+		{ParamName: "session", Source: "http_cookie", DataType: "string"},
+		{ParamName: "email", Source: "http_body", DataType: "string", MinLength: 3, Handler: "signup"},
+		{ParamName: "PORT", Source: "env_var", DataType: "int"},
+	})
+	if doc.OpenAPI != "3.1.0" {
+		t.Errorf("openapi = %q", doc.OpenAPI)
+	}
+	params := doc.Components.Parameters
+	if len(params) != 4 {
+		t.Fatalf("want 4 parameters, got %d: %v", len(params), params)
+	}
+	checks := []struct {
+		key, in  string
+		required bool
+	}{
+		{"SearchQ", "query", false},
+		{"XRequestId", "header", true},
+		{"GetUserId", "path", true},
+		{"Session", "cookie", false},
+	}
+	for _, c := range checks {
+		p := params[c.key]
+		if p == nil {
+			t.Errorf("missing parameter %s", c.key)
+			continue
+		}
+		if p.In != c.in || p.Required != c.required {
+			t.Errorf("%s: in=%q required=%v, want %q %v", c.key, p.In, p.Required, c.in, c.required)
+		}
+	}
+	body := doc.Components.Schemas["SignupBody"]
+	if body == nil || body.Properties["email"] == nil || *body.Properties["email"].MinLength != 3 {
+		t.Errorf("bad body schema: %+v", body)
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Severity levels, lowest first.
+var severities = []string{"info", "low", "medium", "high", "critical"}
+
+// severityRank returns the position of s in severities, or -1 if s is not a
+// known level.
+func severityRank(s string) int {
+	for i, v := range severities {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// typeSeverity is the starting severity of an unguarded boundary that does
+// not visibly reach a sink. Env vars are typically read once at startup from
+// a trusted environment, so they start low.
+var typeSeverity = map[string]string{
+	"http_query":   "medium",
+	"http_path":    "medium",
+	"http_body":    "medium",
+	"http_header":  "medium",
+	"http_cookie":  "medium",
+	"post_message": "medium",
+	"env_var":      "low",
+}
+
+// sinkPatterns recognise dangerous uses of a value, most severe first.
+// Method-style names such as exec and spawn only count as bare calls or on
+// child_process, so RegExp.prototype.exec is not a shell sink.
+var sinkPatterns = []struct {
+	sink     string
+	severity string
+	re       *regexp.Regexp
+}{
+	{"shell", "critical", regexp.MustCompile(
+		`exec\.Command|subprocess\.|os\.system|os\.popen|child_process\.\w+\(|(?:^|[^.\w$])(?:exec|execSync|execFile|spawn|spawnSync)\(|Process\.Start|Command::new|shell_exec|(?:^|[^.\w$])system\(|passthru|proc_open|%x\(`)},
+	{"sql", "critical", regexp.MustCompile(
+		`\.(?:Query|QueryRow|QueryContext|Exec|ExecContext|execute|executemany|raw|query)\(|\b(?:SELECT|INSERT|UPDATE|DELETE)\s|FromSqlRaw|ExecuteSqlRaw|find_by_sql|mysqli_query|DB::(?:select|statement)`)},
+	{"path", "high", regexp.MustCompile(
+		`os\.(?:Open|OpenFile|ReadFile|WriteFile|Create|Remove|RemoveAll)\(|filepath\.Join\(|path\.join\(|\bopen\(|fs\.\w+\(|sendFile\(|send_file|File\.(?:Open|Read|Write)\w*\(|File::open|std::fs::|file_get_contents|fopen\(|\b(?:include|require)(?:_once)?\s*\(`)},
+	{"redirect", "high", regexp.MustCompile(
+		`(?i)\bredirect(?:_to)?\(|\.redirect\(|Results\.Redirect|location\.(?:href|assign|replace)|window\.open\(|['"]Location['"]|Location:`)},
+	{"html", "high", regexp.MustCompile(
+		`innerHTML|outerHTML|document\.write|dangerouslySetInnerHTML|v-html|\{@html|fmt\.Fprint\w*\(\s*w\b|\bw\.Write\(\s*\[\]byte\(|io\.WriteString\(\s*w\b|res\.send\(|render_template_string|Markup\(|mark_safe|\.html_safe|Html\.Raw|template\.HTML\(|(?:^|[;{}]|<\?php)\s*echo\b|<\?=`)},
+}
+
+// guardPatterns recognise validation of a value: bounding its length,
+// matching it against a pattern, parsing it into a narrower type, comparing
+// it with a literal or an allowlist, or passing it to a validator. A line
+// only counts as a guard if it also mentions the traced identifier.
+var guardPatterns = regexp.MustCompile(
+	`\blen\([^()]*\)\s*[<>!=]=?|RuneCountInString\([^()]*\)\s*[<>!=]=?|\.length\s*[<>!=]=?|` +
+		`MatchString\(|\.test\(|\bre\.(?:match|fullmatch)\(|preg_match\(|IsMatch\(|=~|` +
+		`strconv\.(?:Atoi|Parse\w+)\(|\bparse(?:Int|Float)\(|\bNumber\(|\b(?:int|float)\(|Integer\.parseInt\(|` +
+		`\b(?:netip|uuid|time|Guid|DateTime|decimal)\.(?:Try)?Parse\w*\(|TryParse\(|\bintval\(|filter_var\(|` +
+		`(?i:validat|sanitiz|escape|allowlist|whitelist|isValid)|\.safeParse\(|\w+Schema\.parse\(|` +
+		`\bswitch\b|\.includes\(|\bin\s*[(\[{]|(?:==|!=)=?\s*['"]`)
+
+// encoderPatterns recognise a value being encoded for the sink on the same
+// line, such as html.EscapeString or shlex.quote.
+var encoderPatterns = regexp.MustCompile(`(?i:escape|sanitiz|encode|quote)`)
+
+// assignedIdentRe matches the text before a boundary expression when its
+// value is assigned, allowing for a receiver such as "r." that the detector
+// did not include in the match.
+var assignedIdentRe = regexp.MustCompile(`\$?(\w+)(?:\s*,\s*\w+)*\s*(?::\s*[\w.<>\[\]|?]+\s*)?:?=\s*(?:await\s+)?[\w.]*$`)
+
+// maxFlowLines bounds how far below a boundary its value is traced when the
+// end of the enclosing function cannot be determined.
+const maxFlowLines = 60
+
+// traceFlow follows the value read at b through the rest of its enclosing
+// function. It reports the first sink the value reaches and whether it is
+// validated before reaching it. Matching is textual: the value is tracked by
+// the identifier it is assigned to, or on the boundary line itself.
+func traceFlow(lines []string, b Boundary, decl funcDecl, hasDecl bool) (sink string, guarded bool) {
+	if b.Line < 1 || b.Line > len(lines) {
+		return "", false
+	}
+	first := lines[b.Line-1]
+	ident := ""
+	if b.Column >= 1 && b.Column-1 <= len(first) {
+		if m := assignedIdentRe.FindStringSubmatch(first[:b.Column-1]); m != nil {
+			ident = m[1]
+		}
+	}
+	// Blank out the boundary expression itself so that, for example, the
+	// ".Query(" of r.URL.Query() is not mistaken for a SQL call.
+	rest := first
+	if b.EndLine == b.Line && b.EndColumn > b.Column && b.EndColumn-1 <= len(first) {
+		rest = first[:b.Column-1] + strings.Repeat(" ", b.EndColumn-b.Column) + first[b.EndColumn-1:]
+	}
+	// Only guards before the first sink protect it. On the sink line itself
+	// only encoding of the value on its way in counts.
+	check := func(line string) {
+		if sink != "" {
+			return
+		}
+		if sink = matchSink(line); sink != "" {
+			guarded = guarded || encoderPatterns.MatchString(line)
+			return
+		}
+		guarded = guarded || guardPatterns.MatchString(line)
+	}
+	if ident == "" {
+		check(rest)
+		return sink, guarded
+	}
+	// "x := sink(expr)" is a sink use, but "x := expr" alone is not.
+	check(rest[b.Column-1:])
+	identRe := regexp.MustCompile(`(?:^|[^\w$])\$?` + regexp.QuoteMeta(ident) + `\b`)
+	for i := b.EndLine; sink == "" && i < len(lines) && i < b.Line+maxFlowLines; i++ {
+		if hasDecl && declName(decl.re, lines[i]) != "" {
+			break
+		}
+		if identRe.MatchString(lines[i]) {
+			check(lines[i])
+		}
+	}
+	return sink, guarded
+}
+
+func matchSink(line string) string {
+	for _, p := range sinkPatterns {
+		if p.re.MatchString(line) {
+			return p.sink
+		}
+	}
+	return ""
+}
+
+// scoreSeverity rates b from its type, the sink it reaches and whether it is
+// guarded. Request-controlled values take the sink's severity; env vars only
+// step up one level, since they are rarely attacker-controlled.
+func scoreSeverity(b Boundary) string {
+	base, ok := typeSeverity[b.Type]
+	if !ok {
+		base = "medium"
+	}
+	level := severityRank(base)
+	if b.Sink != "" {
+		if b.Type == "env_var" {
+			level++
+		} else {
+			for _, p := range sinkPatterns {
+				if p.sink == b.Sink && severityRank(p.severity) > level {
+					level = severityRank(p.severity)
+				}
+			}
+		}
+	}
+	if b.Guarded && level > 0 {
+		level--
+	}
+	return severities[level]
+}
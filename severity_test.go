@@ -0,0 +1,136 @@
+package main
+
+import "testing"
+
+func TestSeverityFromSinkAndGuard(t *testing.T) {
+	code := "package main\n\n" +
+		"func search(w http.ResponseWriter, r *http.Request) {\n" +
+		"\tq := r.URL.Query().Get(\"q\")\n" +
+		"\trows, _ := db.Query(\"SELECT * FROM t WHERE name = '\" + q + \"'\")\n" +
+		"\t_ = rows\n" +
+		"}\n\n" +
+		"func page(w http.ResponseWriter, r *http.Request) {\n" +
+		"\tp := r.FormValue(\"page\")\n" +
+		"\tn, err := strconv.Atoi(p)\n" +
+		"\tfmt.Fprintf(w, \"%d\", n)\n" +
+		"}\n\n" +
+		"func main() {\n" +
+		"\tport := os.Getenv(\"PORT\")\n" +
+		"\t_ = port\n" +
+		"}\n"
+	bs := ScanContent(code, "main.go", ".go")
+	if len(bs) != 3 {
+		t.Fatalf("want 3 boundaries, got %d", len(bs))
+	}
+	cases := []struct {
+		sink     string
+		guarded  bool
+		severity string
+	}{
+		{"sql", false, "critical"},
+		{"", true, "low"},
+		{"", false, "low"},
+	}
+	for i, c := range cases {
+		b := bs[i]
+		if b.Sink != c.sink || b.Guarded != c.guarded || b.Severity != c.severity {
+			t.Errorf("%s: got sink=%q guarded=%v severity=%s, want sink=%q guarded=%v severity=%s",
+				b.Variable, b.Sink, b.Guarded, b.Severity, c.sink, c.guarded, c.severity)
+		}
+		if b.Confidence != "medium" {
+			t.Errorf("%s: regex match confidence = %q, want medium", b.Variable, b.Confidence)
+		}
+	}
+}
+
+func TestSeverityInlineSink(t *testing.T) {
+	code := "app.get('/run', (req, res) => {\n" +
+		"  child_process.execSync('convert ' + req.query.file)\n" +
+		"})\n"
+	bs := ScanContent(code, "app.js", ".js")
+	if len(bs) != 1 {
+		t.Fatalf("want 1 boundary, got %d", len(bs))
+	}
+	if bs[0].Sink != "shell" || bs[0].Severity != "critical" {
+		t.Errorf("got sink=%q severity=%s, want shell critical", bs[0].Sink, bs[0].Severity)
+	}
+	if v := bs[0].Validation; len(v) == 0 || v[len(v)-1] != sinkSanitizers["shell"].advice {
+		t.Errorf("want the shell remediation last in %q", v)
+	}
+	for _, s := range bs[0].Validation {
+		if s == "sanitize HTML entities" {
+			t.Errorf("want the guessed HTML advice replaced for a shell sink, got %q", bs[0].Validation)
+		}
+	}
+}
+
+func TestSeverityGuardMustPrecedeSink(t *testing.T) {
+	code := "package main\n\n" +
+		"func open(w http.ResponseWriter, r *http.Request) {\n" +
+		"\tname := r.FormValue(\"name\")\n" +
+		"\tlog.Printf(\"%d\", len(name))\n" +
+		"\tf, _ := os.Open(name)\n" +
+		"\tif !validName(name) {\n" +
+		"\t\treturn\n" +
+		"\t}\n" +
+		"}\n\n" +
+		"func greet(w http.ResponseWriter, r *http.Request) {\n" +
+		"\tname := r.FormValue(\"name\")\n" +
+		"\tif len(name) > 64 {\n" +
+		"\t\treturn\n" +
+		"\t}\n" +
+		"\tfmt.Fprintf(w, \"hi %s\", html.EscapeString(name))\n" +
+		"}\n"
+	bs := ScanContent(code, "main.go", ".go")
+	if len(bs) != 2 {
+		t.Fatalf("want 2 boundaries, got %d", len(bs))
+	}
+	if bs[0].Sink != "path" || bs[0].Guarded {
+		t.Errorf("len() alone and a check after the sink: got sink=%q guarded=%v, want path unguarded", bs[0].Sink, bs[0].Guarded)
+	}
+	if bs[1].Sink != "html" || !bs[1].Guarded {
+		t.Errorf("bounded length before the sink: got sink=%q guarded=%v, want html guarded", bs[1].Sink, bs[1].Guarded)
+	}
+}
+
+func TestSeverityLookalikeSinks(t *testing.T) {
+	for _, c := range []struct{ name, code, ext, want string }{
+		{"RegExp exec", "const q = new URLSearchParams(location.search).get(\"q\")\nconst m = /^(\\w+)$/.exec(q)\n", ".js", ""},
+		{"bare exec", "const q = new URLSearchParams(location.search).get(\"q\")\nexec(`grep ${q}`)\n", ".js", "shell"},
+		{"buffer Write", "package main\n\nfunc h(w http.ResponseWriter, r *http.Request) {\n\tq := r.FormValue(\"q\")\n\tbuf.Write(q)\n\tw.Write(data)\n}\n", ".go", ""},
+		{"response Write", "package main\n\nfunc h(w http.ResponseWriter, r *http.Request) {\n\tq := r.FormValue(\"q\")\n\tw.Write([]byte(q))\n}\n", ".go", "html"},
+		{"echo in a string", "const q = req.query.q\nlog(\"echo\", q)\n", ".js", ""},
+		{"PHP echo", "<?php\n$q = $_GET['q'];\necho $q;\n", ".php", "html"},
+	} {
+		bs := ScanContent(c.code, "x"+c.ext, c.ext)
+		if len(bs) == 0 {
+			t.Errorf("%s: no boundary found", c.name)
+			continue
+		}
+		if bs[0].Sink != c.want {
+			t.Errorf("%s: got sink %q, want %q", c.name, bs[0].Sink, c.want)
+		}
+	}
+}
+
+func TestConfidenceFromAST(t *testing.T) {
+	code := "package config\n\ntype C struct {\n\tPort int `env:\"PORT\"`\n}\n"
+	bs := ScanContent(code, "config.go", ".go")
+	if len(bs) != 1 || bs[0].Confidence != "high" {
+		t.Fatalf("want one high-confidence boundary, got %+v", bs)
+	}
+}
+
+func TestScoreSeverityEnvVarSink(t *testing.T) {
+	b := Boundary{Type: "env_var", Sink: "shell"}
+	if got := scoreSeverity(b); got != "medium" {
+		t.Errorf("env var reaching shell: got %s, want medium", got)
+	}
+	b.Guarded = true
+	if got := scoreSeverity(b); got != "low" {
+		t.Errorf("guarded env var reaching shell: got %s, want low", got)
+	}
+	if severityRank("high") <= severityRank("medium") || severityRank("bogus") != -1 {
+		t.Error("severity ranks out of order")
+	}
+}
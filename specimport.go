@@ -0,0 +1,437 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// specIn maps OpenAPI and Swagger parameter locations to boundary types.
+var specIn = map[string]string{
+	"query":    "http_query",
+	"header":   "http_header",
+	"path":     "http_path",
+	"cookie":   "http_cookie",
+	"body":     "http_body",
+	"formData": "http_body",
+}
+
+var specMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// LoadSpec reads an OpenAPI 3.x or Swagger 2.0 document in JSON form and
+// returns an entry for every operation parameter and request body property,
+// with Handler set to the operationId (or "METHOD /path") and Source to the
+// boundary type of its location.
+func LoadSpec(path string) ([]BoundaryEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		// YAML would need a third-party parser; convert first, for example
+		// with "yq -o=json".
+		if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" {
+			return nil, fmt.Errorf("%s: YAML specs are not supported, convert to JSON first (e.g. yq -o=json)", path)
+		}
+		return nil, fmt.Errorf("%s: only JSON specs are supported: %w", path, err)
+	}
+	if doc["openapi"] == nil && doc["swagger"] == nil {
+		return nil, fmt.Errorf("%s: not an OpenAPI or Swagger document", path)
+	}
+	s := specDoc{root: doc, expanding: map[string]bool{}}
+	return s.entries(), nil
+}
+
+type specDoc struct {
+	root map[string]any
+	// expanding holds the $refs of the schemas being expanded, so that a
+	// recursive schema is not expanded again inside itself.
+	expanding map[string]bool
+}
+
+// resolve follows a local $ref such as "#/components/schemas/User".
+func (s specDoc) resolve(v any) map[string]any {
+	m, _ := s.follow(v)
+	return m
+}
+
+// follow is resolve that also returns the $refs it followed.
+func (s specDoc) follow(v any) (map[string]any, []string) {
+	m, _ := v.(map[string]any)
+	var refs []string
+	for i := 0; i < 32 && m != nil; i++ {
+		ref, ok := m["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#/") {
+			return m, refs
+		}
+		refs = append(refs, ref)
+		var cur any = s.root
+		for _, part := range strings.Split(ref[2:], "/") {
+			part = strings.NewReplacer("~1", "/", "~0", "~").Replace(part)
+			next, _ := cur.(map[string]any)
+			cur = next[part]
+		}
+		m, _ = cur.(map[string]any)
+	}
+	return m, refs
+}
+
+// expand resolves v and passes the schema to f, unless v refers back to a
+// schema that is already being expanded.
+func (s specDoc) expand(v any, f func(map[string]any)) {
+	m, refs := s.follow(v)
+	if m == nil {
+		return
+	}
+	for _, ref := range refs {
+		if s.expanding[ref] {
+			return
+		}
+	}
+	for _, ref := range refs {
+		s.expanding[ref] = true
+	}
+	defer func() {
+		for _, ref := range refs {
+			delete(s.expanding, ref)
+		}
+	}()
+	f(m)
+}
+
+func (s specDoc) entries() []BoundaryEntry {
+	var out []BoundaryEntry
+	paths, _ := s.root["paths"].(map[string]any)
+	routes := make([]string, 0, len(paths))
+	for r := range paths {
+		routes = append(routes, r)
+	}
+	sort.Strings(routes)
+	for _, route := range routes {
+		item := s.resolve(paths[route])
+		for _, method := range specMethods {
+			op := s.resolve(item[method])
+			if op == nil {
+				continue
+			}
+			handler, _ := op["operationId"].(string)
+			if handler == "" {
+				handler = strings.ToUpper(method) + " " + route
+			}
+			params, _ := item["parameters"].([]any)
+			opParams, _ := op["parameters"].([]any)
+			for _, p := range append(params, opParams...) {
+				out = append(out, s.paramEntries(handler, s.resolve(p))...)
+			}
+			if body := s.resolve(op["requestBody"]); body != nil {
+				content, _ := body["content"].(map[string]any)
+				for _, mt := range []string{"application/json", "application/x-www-form-urlencoded", "multipart/form-data"} {
+					if media, ok := content[mt].(map[string]any); ok {
+						out = append(out, s.propertyEntries(handler, s.resolve(media["schema"]))...)
+						break
+					}
+				}
+			}
+		}
+	}
+	return out
+}
+
+func (s specDoc) paramEntries(handler string, p map[string]any) []BoundaryEntry {
+	name, _ := p["name"].(string)
+	in, _ := p["in"].(string)
+	src, ok := specIn[in]
+	if p == nil || !ok {
+		return nil
+	}
+	if in == "body" {
+		// Swagger 2.0 describes the whole body as one parameter.
+		return s.propertyEntries(handler, s.resolve(p["schema"]))
+	}
+	schema := s.resolve(p["schema"])
+	if schema == nil {
+		schema = p // Swagger 2.0 keeps the constraints on the parameter.
+	}
+	e := s.specEntry(name, handler, src, schema)
+	if req, _ := p["required"].(bool); req {
+		requireEntry(&e)
+	}
+	return []BoundaryEntry{e}
+}
+
+// requireEntry marks a required string input by its minimum length, which is
+// how entries say they must be present.
+func requireEntry(e *BoundaryEntry) {
+	if (e.DataType == "string" || e.DataType == "enum") && e.MinLength == 0 {
+		e.MinLength = 1
+	}
+}
+
+func (s specDoc) propertyEntries(handler string, schema map[string]any) []BoundaryEntry {
+	props, _ := schema["properties"].(map[string]any)
+	names := make([]string, 0, len(props))
+	for n := range props {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	required := map[string]bool{}
+	reqs, _ := schema["required"].([]any)
+	for _, r := range reqs {
+		if n, ok := r.(string); ok {
+			required[n] = true
+		}
+	}
+	var out []BoundaryEntry
+	for _, n := range names {
+		// A property that recurses into its own schema is left out below
+		// the first level.
+		s.expand(props[n], func(m map[string]any) {
+			e := s.specEntry(n, handler, "http_body", m)
+			if required[n] {
+				requireEntry(&e)
+			}
+			out = append(out, e)
+		})
+	}
+	return out
+}
+
+// specEntry converts a parameter or property schema into an entry. Array
+// items and the properties of nested objects become entries of their own.
+func (s specDoc) specEntry(name, handler, source string, schema map[string]any) BoundaryEntry {
+	e := BoundaryEntry{ParamName: name, Handler: handler, Source: source, DataType: "string"}
+	num := func(key string) (float64, bool) {
+		v, ok := schema[key].(float64)
+		return v, ok
+	}
+	switch schema["type"] {
+	case "number":
+		e.DataType = "float64"
+		lo, hasLo := num("minimum")
+		hi, hasHi := num("maximum")
+		if hasLo || hasHi {
+			e.MinFloat, e.MaxFloat = -math.MaxFloat64, math.MaxFloat64
+		}
+		if hasLo {
+			e.MinFloat = lo
+		}
+		if hasHi {
+			e.MaxFloat = hi
+		}
+	case "boolean":
+		e.DataType = "bool"
+	case "array":
+		e.DataType = "list"
+		if v, ok := num("minItems"); ok {
+			e.MinItems = int(v)
+		}
+		if v, ok := num("maxItems"); ok {
+			e.MaxItems = int(v)
+		}
+		e.UniqueItems, _ = schema["uniqueItems"].(bool)
+		s.expand(schema["items"], func(m map[string]any) {
+			item := s.specEntry("", handler, source, m)
+			e.Items = &item
+		})
+		return e
+	case "object":
+		e.DataType = "object"
+		e.Fields = s.propertyEntries(handler, schema)
+		for i := range e.Fields {
+			e.Fields[i].Source = source
+		}
+		return e
+	case "integer":
+		e.DataType = "int"
+		lo, hasLo := num("minimum")
+		hi, hasHi := num("maximum")
+		if hasLo || hasHi {
+			// An open end becomes the widest int64 bound.
+			e.MinValue, e.MaxValue = math.MinInt64, math.MaxInt64
+		}
+		if hasLo {
+			e.MinValue = int64(math.Ceil(lo))
+		}
+		if hasHi {
+			e.MaxValue = int64(math.Floor(hi))
+		}
+	}
+	if v, ok := num("maxLength"); ok {
+		e.MaxLength = int(v)
+	}
+	if v, ok := num("minLength"); ok {
+		e.MinLength = int(v)
+	}
+	if enum, ok := schema["enum"].([]any); ok {
+		for _, v := range enum {
+			e.EnumValues = append(e.EnumValues, fmt.Sprint(v))
+		}
+		if e.DataType == "string" {
+			e.DataType = "enum"
+		}
+	}
+	e.RegexPattern, _ = schema["pattern"].(string)
+	if f, ok := schema["format"].(string); ok && e.DataType == "string" {
+		e.Format = specFormats[f]
+		if e.Format == "" {
+			e.Format = "none" // declared, but not one we check
+		}
+	}
+	return e
+}
+
+// MergeSpec applies the constraints spec declares to the scanned entries
+// they match by source and name, as CrossCheck matches them, keeping the
+// scanned handler, file and sink. Scanned entries the spec does not declare
+// keep their default suggestions, and spec entries no scanned entry matched,
+// including an input another operation also declares, are added at the end. Where several operations declare the same input, the one
+// whose operationId matches the scanned handler wins, then the first.
+func MergeSpec(scanned, spec []BoundaryEntry) []BoundaryEntry {
+	declared := map[string][]int{} // indexes into spec
+	for i, e := range spec {
+		k := specKey(e.Source, e.ParamName)
+		declared[k] = append(declared[k], i)
+	}
+	used := make([]bool, len(spec))
+	out := make([]BoundaryEntry, 0, len(scanned))
+	for _, e := range scanned {
+		cands := declared[specKey(e.Source, fieldOf(e.ParamName))]
+		if len(cands) == 0 {
+			out = append(out, e)
+			continue
+		}
+		i := cands[0]
+		for _, c := range cands {
+			if strings.EqualFold(spec[c].Handler, e.Handler) {
+				i = c
+				break
+			}
+		}
+		used[i] = true
+		m := spec[i]
+		m.ParamName, m.Handler, m.File, m.Sink = e.ParamName, e.Handler, e.File, e.Sink
+		out = append(out, m)
+	}
+	for i, e := range spec {
+		if !used[i] {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// specFormats maps OpenAPI and JSON Schema format names to ours.
+var specFormats = map[string]string{
+	"email": "email", "uuid": "uuid", "uri": "url", "url": "url", "hostname": "hostname",
+	"ipv4": "ip", "ipv6": "ip", "date": "date", "date-time": "date-time", "duration": "duration",
+	"byte": "base64",
+}
+
+// SpecFinding is a disagreement between a spec and the scanned code.
+type SpecFinding struct {
+	Kind     string `json:"kind"` // "undeclared" or "unvalidated"
+	Source   string `json:"source"`
+	Name     string `json:"name"`
+	Handler  string `json:"handler,omitempty"`
+	Location string `json:"location,omitempty"`
+	Detail   string `json:"detail"`
+	// Confidence is "high" when the finding only depends on which inputs are
+	// read, and lower when it depends on whether a read is guarded.
+	Confidence string `json:"confidence"`
+}
+
+// specKey identifies an input by boundary type and name, ignoring the case of
+// header names.
+func specKey(src, name string) string {
+	if src == "http_header" {
+		name = strings.ToLower(name)
+	}
+	return src + "\x00" + name
+}
+
+// CrossCheck compares spec entries with scanned HTTP boundaries, matching
+// them by type and name (case-insensitively for headers). It reports inputs
+// the code reads that the spec does not declare, and declared inputs that
+// the code never reads or reads without validating. Whether a read is
+// validated comes from the textual Guarded check, which does not see
+// validation in helpers, middleware or struct tags, so those findings are
+// "medium" confidence when the value reaches a sink and "low" otherwise.
+func CrossCheck(spec []BoundaryEntry, bs []Boundary) []SpecFinding {
+	declared := map[string]bool{}
+	for _, e := range spec {
+		declared[specKey(e.Source, e.ParamName)] = true
+	}
+	var out []SpecFinding
+	reads := map[string][]Boundary{}
+	for _, b := range bs {
+		if !strings.HasPrefix(b.Type, "http_") {
+			continue
+		}
+		k := specKey(b.Type, fieldOf(b.Variable))
+		reads[k] = append(reads[k], b)
+		if !declared[k] && len(reads[k]) == 1 {
+			out = append(out, SpecFinding{
+				Kind: "undeclared", Source: b.Type, Name: b.Variable, Handler: b.Function,
+				Location:   fmt.Sprintf("%s:%d", b.File, b.Line),
+				Detail:     "read by the code but not declared in the spec",
+				Confidence: "high",
+			})
+		}
+	}
+	reported := map[string]bool{}
+	for _, e := range spec {
+		k := specKey(e.Source, e.ParamName)
+		if reported[k] {
+			continue
+		}
+		reported[k] = true
+		rs := reads[k]
+		if len(rs) == 0 {
+			out = append(out, SpecFinding{
+				Kind: "unvalidated", Source: e.Source, Name: e.ParamName, Handler: e.Handler,
+				Detail:     "declared in the spec but never read by the code",
+				Confidence: "high",
+			})
+			continue
+		}
+		guarded := false
+		at := rs[0]
+		for _, b := range rs {
+			guarded = guarded || b.Guarded
+			if at.Sink == "" && b.Sink != "" {
+				at = b
+			}
+		}
+		if guarded {
+			continue
+		}
+		f := SpecFinding{
+			Kind: "unvalidated", Source: e.Source, Name: e.ParamName, Handler: e.Handler,
+			Location:   fmt.Sprintf("%s:%d", at.File, at.Line),
+			Detail:     "declared in the spec but no validation was found where the code reads it",
+			Confidence: "low",
+		}
+		if at.Sink != "" {
+			f.Detail = fmt.Sprintf("declared in the spec but reaches a %s sink with no validation found before it", at.Sink)
+			f.Confidence = "medium"
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+// fieldOf returns the innermost field of a nested form name such as
+// "user[name]", which is how request bodies are often read.
+func fieldOf(v string) string {
+	if strings.HasSuffix(v, "]") {
+		if i := strings.LastIndex(v, "["); i >= 0 {
+			return v[i+1 : len(v)-1]
+		}
+	}
+	return v
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const openAPI3Spec = `{
+  "openapi": "3.1.0",
+  "paths": {
+    "/users/{id}": {
+      "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "integer", "minimum": 1}}],
+      "get": {
+        "operationId": "getUser",
+        "parameters": [
+          {"$ref": "#/components/parameters/Fields"},
+          {"name": "X-Trace", "in": "header", "schema": {"type": "string", "pattern": "^[a-f0-9]{16}$"}}
+        ]
+      },
+      "put": {
+        "requestBody": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}}
+      }
+    }
+  },
+  "components": {
+    "parameters": {
+      "Fields": {"name": "fields", "in": "query", "required": true, "schema": {"type": "string", "maxLength": 200}}
+    },
+    "schemas": {
+      "User": {"type": "object", "required": ["role"], "properties": {
+        "name": {"type": "string", "minLength": 1, "maxLength": 64},
+        "role": {"type": "string", "enum": ["admin", "user"]},
+        "tags": {"type": "array", "maxItems": 5, "uniqueItems": true, "items": {"type": "string", "maxLength": 16}},
+        "geo": {"type": "object", "properties": {
+          "lat": {"type": "number", "minimum": -90, "maximum": 90},
+          "exact": {"type": "boolean"}
+        }}
+      }}
+    }
+  }
+}`
+
+const swagger2Spec = `{
+  "swagger": "2.0",
+  "paths": {
+    "/login": {
+      "post": {
+        "operationId": "login",
+        "parameters": [
+          {"name": "user", "in": "formData", "type": "string", "maxLength": 32},
+          {"name": "body", "in": "body", "schema": {"$ref": "#/definitions/Creds"}}
+        ]
+      }
+    }
+  },
+  "definitions": {"Creds": {"properties": {"otp": {"type": "integer", "minimum": 0, "maximum": 999999}}}}
+}`
+
+func loadSpecString(t *testing.T, src string) []BoundaryEntry {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "spec.json")
+	mustWrite(t, path, src)
+	es, err := LoadSpec(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return es
+}
+
+func findEntry(es []BoundaryEntry, name string) *BoundaryEntry {
+	for i := range es {
+		if es[i].ParamName == name {
+			return &es[i]
+		}
+	}
+	return nil
+}
+
+func TestLoadSpecOpenAPI3(t *testing.T) {
+	es := loadSpecString(t, openAPI3Spec)
+	if len(es) != 8 {
+		t.Fatalf("want 8 entries (id twice, fields, X-Trace, name, role, tags, geo), got %d: %+v", len(es), es)
+	}
+	id := findEntry(es, "id")
+	if id.Source != "http_path" || id.DataType != "int" || id.MinValue != 1 || id.Handler != "getUser" {
+		t.Errorf("bad id: %+v", id)
+	}
+	if f := findEntry(es, "fields"); f.Source != "http_query" || f.MaxLength != 200 || f.MinLength != 1 {
+		t.Errorf("$ref parameter not resolved or required ignored: %+v", f)
+	}
+	if x := findEntry(es, "X-Trace"); x.Source != "http_header" || x.RegexPattern != "^[a-f0-9]{16}$" {
+		t.Errorf("bad header: %+v", x)
+	}
+	role := findEntry(es, "role")
+	if role.Source != "http_body" || role.DataType != "enum" || len(role.EnumValues) != 2 || role.Handler != "PUT /users/{id}" {
+		t.Errorf("bad body property: %+v", role)
+	}
+	if role.MinLength != 1 {
+		t.Errorf("required property: want MinLength 1, got %+v", role)
+	}
+	tags := findEntry(es, "tags")
+	if tags.DataType != "list" || tags.MaxItems != 5 || !tags.UniqueItems || tags.Items == nil || tags.Items.MaxLength != 16 {
+		t.Errorf("bad array property: %+v", tags)
+	}
+	geo := findEntry(es, "geo")
+	if geo.DataType != "object" || len(geo.Fields) != 2 {
+		t.Fatalf("bad object property: %+v", geo)
+	}
+	if exact, lat := geo.Fields[0], geo.Fields[1]; exact.DataType != "bool" || lat.DataType != "float64" || lat.MinFloat != -90 || lat.MaxFloat != 90 {
+		t.Errorf("bad nested properties: %+v", geo.Fields)
+	}
+}
+
+func TestLoadSpecSwagger2(t *testing.T) {
+	es := loadSpecString(t, swagger2Spec)
+	if len(es) != 2 {
+		t.Fatalf("want 2 entries, got %+v", es)
+	}
+	if u := findEntry(es, "user"); u.Source != "http_body" || u.MaxLength != 32 {
+		t.Errorf("bad formData param: %+v", u)
+	}
+	if o := findEntry(es, "otp"); o.DataType != "int" || o.MaxValue != 999999 {
+		t.Errorf("bad body schema property: %+v", o)
+	}
+}
+
+func TestLoadSpecRecursiveSchema(t *testing.T) {
+	es := loadSpecString(t, `{
+  "openapi": "3.0.3",
+  "paths": {"/nodes": {"post": {"operationId": "createNode",
+    "requestBody": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Node"}}}}}}},
+  "components": {"schemas": {"Node": {"type": "object", "properties": {
+    "name": {"type": "string", "maxLength": 32},
+    "children": {"type": "array", "items": {"$ref": "#/components/schemas/Node"}},
+    "parent": {"$ref": "#/components/schemas/Node"}
+  }}}}
+}`)
+	children := findEntry(es, "children")
+	if children == nil || children.Items == nil || children.Items.DataType != "object" {
+		t.Fatalf("want children expanded once, got %+v", children)
+	}
+	inner, name := findEntry(children.Items.Fields, "children"), findEntry(children.Items.Fields, "name")
+	if inner == nil || inner.Items != nil || name == nil || name.MaxLength != 32 {
+		t.Errorf("want the recursion cut at the repeated $ref, got %+v", children.Items.Fields)
+	}
+	if len(children.Items.Fields) != 2 {
+		t.Errorf("want the recursive parent property left out, got %+v", children.Items.Fields)
+	}
+}
+
+func TestLoadSpecRejectsOtherJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "x.json")
+	mustWrite(t, path, `{"name": "pkg"}`)
+	if _, err := LoadSpec(path); err == nil {
+		t.Error("want an error for a non-spec document")
+	}
+}
+
+func TestLoadSpecYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "openapi.yaml")
+	mustWrite(t, path, "openapi: 3.1.0\npaths: {}\n")
+	if _, err := LoadSpec(path); err == nil || !strings.Contains(err.Error(), "convert to JSON") {
+		t.Errorf("want a convert-to-JSON error for YAML, got %v", err)
+	}
+}
+
+func TestMergeSpec(t *testing.T) {
+	scanned := []BoundaryEntry{
+		{ParamName: "fields", Handler: "GetUser", Source: "http_query", File: "h.go", Sink: "sql", DataType: "string", MaxLength: 1024},
+		{ParamName: "debug", Handler: "GetUser", Source: "http_query", File: "h.go", DataType: "string", MaxLength: 1024},
+	}
+	spec := loadSpecString(t, openAPI3Spec)
+	got := MergeSpec(scanned, spec)
+	if len(got) != 1+len(spec) {
+		t.Fatalf("want the undeclared input kept and the unread spec entries added, got %d entries", len(got))
+	}
+	if e := got[0]; e.MaxLength != 200 || e.Handler != "GetUser" || e.File != "h.go" || e.Sink != "sql" {
+		t.Errorf("declared input: want the spec's limit with the scanned location, got %+v", e)
+	}
+	if e := got[1]; e.ParamName != "debug" || e.MaxLength != 1024 {
+		t.Errorf("undeclared input: want the default suggestion kept, got %+v", e)
+	}
+	if findEntry(got[2:], "fields") != nil {
+		t.Error("a matched spec entry should not be added again")
+	}
+}
+
+func TestMergeSpecPerOperation(t *testing.T) {
+	scanned := []BoundaryEntry{{ParamName: "id", Handler: "getUser", Source: "http_path", File: "h.go", DataType: "string"}}
+	spec := loadSpecString(t, openAPI3Spec)
+	got := MergeSpec(scanned, spec)
+	var ids []string
+	for _, e := range got {
+		if e.ParamName == "id" {
+			ids = append(ids, e.Handler)
+		}
+	}
+	if len(ids) != 2 || ids[0] != "getUser" || ids[1] != "PUT /users/{id}" {
+		t.Errorf("want the read id merged and the other operation's id added, got handlers %q", ids)
+	}
+}
+
+func TestCrossCheck(t *testing.T) {
+	spec := []BoundaryEntry{
+		{ParamName: "q", Source: "http_query"},
+		{ParamName: "X-Trace", Source: "http_header"},
+		{ParamName: "page", Source: "http_query"},
+		{ParamName: "name", Source: "http_body"},
+		{ParamName: "cmd", Source: "http_body"},
+	}
+	bs := []Boundary{
+		{File: "a.go", Line: 3, Type: "http_query", Variable: "q", Guarded: true},
+		{File: "a.go", Line: 4, Type: "http_header", Variable: "x-trace"},
+		{File: "a.go", Line: 5, Type: "http_query", Variable: "debug"},
+		{File: "a.rb", Line: 2, Type: "http_body", Variable: "user[name]", Guarded: true},
+		{File: "a.go", Line: 6, Type: "env_var", Variable: "PORT"},
+		{File: "a.go", Line: 7, Type: "http_body", Variable: "cmd", Sink: "shell"},
+	}
+	got, conf := map[string]string{}, map[string]string{}
+	for _, f := range CrossCheck(spec, bs) {
+		got[f.Name] = f.Kind + ": " + f.Detail
+		conf[f.Name] = f.Confidence
+	}
+	if conf["debug"] != "high" || conf["cmd"] != "medium" || conf["X-Trace"] != "low" {
+		t.Errorf("confidences: got %v", conf)
+	}
+	want := map[string]string{
+		"X-Trace": "unvalidated: declared in the spec but no validation was found where the code reads it",
+		"debug":   "undeclared: read by the code but not declared in the spec",
+		"page":    "unvalidated: declared in the spec but never read by the code",
+		"cmd":     "unvalidated: declared in the spec but reaches a shell sink with no validation found before it",
+	}
+	if len(got) != len(want) {
+		t.Errorf("got findings %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s: got %q, want %q", k, got[k], v)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// BoundaryWriter receives boundaries one at a time as files are scanned, so
+// large repositories can be reported without holding every finding in memory.
+type BoundaryWriter interface {
+	Write(b Boundary) error
+	// Flush is called after each file.
+	Flush() error
+}
+
+type ndjsonWriter struct {
+	enc *json.Encoder
+}
+
+// NewNDJSONWriter writes each boundary as one JSON object per line.
+func NewNDJSONWriter(w io.Writer) BoundaryWriter {
+	return &ndjsonWriter{enc: json.NewEncoder(w)}
+}
+
+func (n *ndjsonWriter) Write(b Boundary) error { return n.enc.Encode(b) }
+func (n *ndjsonWriter) Flush() error           { return nil }
+
+// csvHeader lists the columns written by NewCSVWriter.
+var csvHeader = []string{
+	"file", "line", "column", "end_line", "end_column", "function", "type", "source",
+	"variable", "severity", "confidence", "sink", "guarded", "fingerprint", "validation_rules",
+}
+
+type csvWriter struct {
+	w      *csv.Writer
+	header bool
+}
+
+// NewCSVWriter writes boundaries as CSV rows for spreadsheet triage. The
+// header row is written before the first boundary, or on the first Flush if
+// none were found.
+func NewCSVWriter(w io.Writer) BoundaryWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) Write(b Boundary) error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	return c.w.Write([]string{
+		b.File,
+		strconv.Itoa(b.Line),
+		strconv.Itoa(b.Column),
+		strconv.Itoa(b.EndLine),
+		strconv.Itoa(b.EndColumn),
+		b.Function,
+		b.Type,
+		b.Source,
+		b.Variable,
+		b.Severity,
+		b.Confidence,
+		b.Sink,
+		strconv.FormatBool(b.Guarded),
+		b.Fingerprint,
+		strings.Join(b.Validation, "; "),
+	})
+}
+
+func (c *csvWriter) Flush() error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) writeHeader() error {
+	if c.header {
+		return nil
+	}
+	c.header = true
+	return c.w.Write(csvHeader)
+}
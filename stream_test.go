@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNDJSONWriter(t *testing.T) {
+	var buf strings.Builder
+	w := NewNDJSONWriter(&buf)
+	for _, v := range []string{"a", "b"} {
+		if err := w.Write(Boundary{File: "x.go", Line: 1, Variable: v}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	sc := bufio.NewScanner(strings.NewReader(buf.String()))
+	var got []string
+	for sc.Scan() {
+		var b Boundary
+		if err := json.Unmarshal(sc.Bytes(), &b); err != nil {
+			t.Fatalf("line %q is not a JSON object: %v", sc.Text(), err)
+		}
+		got = append(got, b.Variable)
+	}
+	if strings.Join(got, ",") != "a,b" {
+		t.Errorf("want one line per boundary, got %v", got)
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	var buf strings.Builder
+	w := NewCSVWriter(&buf)
+	b := Boundary{File: "a,b.go", Line: 3, Type: "http_query", Variable: `say "hi"`,
+		Severity: "high", Guarded: true, Validation: []string{"check non-empty", "max length 1024"}}
+	if err := w.Write(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || len(rows[1]) != len(csvHeader) {
+		t.Fatalf("want header and one row, got %q", rows)
+	}
+	row := rows[1]
+	if row[0] != "a,b.go" || row[1] != "3" || row[8] != `say "hi"` || row[12] != "true" || row[14] != "check non-empty; max length 1024" {
+		t.Errorf("bad row: %q", row)
+	}
+}
+
+func TestCSVWriterHeaderOnly(t *testing.T) {
+	var buf strings.Builder
+	w := NewCSVWriter(&buf)
+	w.Flush()
+	w.Flush()
+	if got := strings.Count(buf.String(), "\n"); got != 1 {
+		t.Errorf("want a single header line, got %q", buf.String())
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Summary holds the aggregate counts that dashboards would otherwise
+// recompute from the flat boundary list.
+type Summary struct {
+	ByType      map[string]int `json:"by_type"`
+	BySource    map[string]int `json:"by_source"`
+	ByLanguage  map[string]int `json:"by_language"`
+	ByDirectory map[string]int `json:"by_directory"`
+	// Files maps every scanned file to its boundary count, including zero.
+	Files map[string]int `json:"files"`
+}
+
+// SkippedFile records a file the walker did not scan and why.
+type SkippedFile struct {
+	File   string `json:"file"`
+	Reason string `json:"reason"`
+}
+
+// Summarize aggregates bs, found while scanning files.
+func Summarize(files []string, bs []Boundary) Summary {
+	s := Summary{
+		ByType:      map[string]int{},
+		BySource:    map[string]int{},
+		ByLanguage:  map[string]int{},
+		ByDirectory: map[string]int{},
+		Files:       map[string]int{},
+	}
+	for _, f := range files {
+		s.Files[filepath.ToSlash(f)] = 0
+	}
+	for _, b := range bs {
+		s.ByType[b.Type]++
+		s.BySource[b.Source]++
+		s.ByLanguage[fileLanguage(b.File)]++
+		s.ByDirectory[filepath.ToSlash(filepath.Dir(b.File))]++
+		s.Files[filepath.ToSlash(b.File)]++
+	}
+	return s
+}
+
+// ruleVersioner is implemented by detectors whose rules are code rather than
+// regex rules, so that RuleSetHash changes when that code does.
+type ruleVersioner interface {
+	RulesVersion() int
+}
+
+// RuleSetHash identifies the detection rules in effect: the tool version,
+// each registered language with its extensions and regex rules or, for
+// detectors written as code, their rules version, and the sink, guard and
+// encoder patterns. Findings from runs with different hashes are not
+// directly comparable.
+func RuleSetHash() string {
+	h := sha256.New()
+	fmt.Fprintln(h, version)
+	for _, r := range detectors {
+		fmt.Fprintf(h, "%s %s %T\n", r.lang, strings.Join(r.d.Extensions(), ","), r.d)
+		switch d := r.d.(type) {
+		case *regexDetector:
+			for _, ru := range append(d.rules, d.multiline...) {
+				fmt.Fprintf(h, "%s\x00%s\x00%d\x00%s\n", ru.typ, ru.source, ru.idx, ru.re)
+			}
+		case ruleVersioner:
+			fmt.Fprintf(h, "v%d\n", d.RulesVersion())
+		}
+	}
+	for _, p := range sinkPatterns {
+		fmt.Fprintf(h, "%s\x00%s\x00%s\n", p.sink, p.severity, p.re)
+	}
+	fmt.Fprintln(h, guardPatterns)
+	fmt.Fprintln(h, encoderPatterns)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,75 @@
+package main
+
+import "testing"
+
+func TestSummarize(t *testing.T) {
+	bs := []Boundary{
+		{File: "api/h.go", Type: "http_query", Source: "URL Query"},
+		{File: "api/h.go", Type: "http_header", Source: "Header"},
+		{File: "web/app.py", Type: "http_query", Source: "Flask args"},
+	}
+	s := Summarize([]string{"api/h.go", "api/clean.go", "web/app.py"}, bs)
+	checks := []struct {
+		name string
+		got  int
+		want int
+	}{
+		{"by_type http_query", s.ByType["http_query"], 2},
+		{"by_source Header", s.BySource["Header"], 1},
+		{"by_language Go", s.ByLanguage["Go"], 2},
+		{"by_language Python", s.ByLanguage["Python"], 1},
+		{"by_directory api", s.ByDirectory["api"], 2},
+		{"files api/h.go", s.Files["api/h.go"], 2},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s: want %d, got %d", c.name, c.want, c.got)
+		}
+	}
+	if n, ok := s.Files["api/clean.go"]; !ok || n != 0 {
+		t.Errorf("clean files should be listed with 0, got %d (present %v)", n, ok)
+	}
+}
+
+func TestRuleSetHash(t *testing.T) {
+	h := RuleSetHash()
+	if len(h) != 64 || h != RuleSetHash() {
+		t.Fatalf("want a stable sha256 hex digest, got %q", h)
+	}
+	saved := detectors
+	defer func() { detectors = saved }()
+	RegisterDetector("Test", &regexDetector{exts: []string{".test"}})
+	if RuleSetHash() == h {
+		t.Error("registering a detector should change the hash")
+	}
+}
+
+func TestRuleSetHashCoversCodeDetectors(t *testing.T) {
+	for _, r := range detectors {
+		switch d := r.d.(type) {
+		case *regexDetector:
+		case ruleVersioner:
+			if d.RulesVersion() < 1 {
+				t.Errorf("%s %T: rules version must be set", r.lang, r.d)
+			}
+		default:
+			t.Errorf("%s %T: neither regex rules nor a rules version for RuleSetHash", r.lang, r.d)
+		}
+	}
+
+	h := RuleSetHash()
+	saved := detectors
+	defer func() { detectors = saved }()
+	detectors = append([]registered(nil), saved...)
+	for i, r := range detectors {
+		if fd, ok := r.d.(*funcDetector); ok {
+			bumped := *fd
+			bumped.version++
+			detectors[i].d = &bumped
+			break
+		}
+	}
+	if RuleSetHash() == h {
+		t.Error("bumping a detector's rules version should change the hash")
+	}
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// lengthUnits are the values accepted in BoundaryEntry.LengthUnit.
+var lengthUnits = map[string]bool{"": true, "bytes": true, "runes": true, "graphemes": true}
+
+// bidiControls are the Unicode bidirectional formatting characters, which
+// can reorder displayed text ("Trojan Source"). NoControl rejects them along
+// with the C0 and C1 controls.
+const bidiControls = "\u061C\u200E\u200F\u202A\u202B\u202C\u202D\u202E\u2066\u2067\u2068\u2069"
+
+// checkLengthUnit reports an unknown LengthUnit on e.
+func checkLengthUnit(e BoundaryEntry, label string) error {
+	if !lengthUnits[e.LengthUnit] {
+		return fmt.Errorf("%s: unknown length unit %q", label, e.LengthUnit)
+	}
+	return nil
+}
+
+// lengthUnit returns the unit e's lengths count in, so that every generator
+// reads the default "" as bytes.
+func lengthUnit(e BoundaryEntry) string {
+	if e.LengthUnit == "" {
+		return "bytes"
+	}
+	return e.LengthUnit
+}
+
+// goLength returns the Go expression for the length of v in unit: bytes by
+// default, runes or approximate grapheme clusters.
+func goLength(unit, v string) string {
+	switch unit {
+	case "runes":
+		return "utf8.RuneCountInString(" + v + ")"
+	case "graphemes":
+		return "graphemeLen(" + v + ")"
+	}
+	return "len(" + v + ")"
+}
+
+// goUnsafeText is the Go condition that holds when v contains a control or
+// bidi formatting character.
+func goUnsafeText(v string) string {
+	return "strings.IndexFunc(" + v + ", unsafeRune) >= 0"
+}
+
+// jsLength returns the JavaScript expression for the length of x in unit:
+// UTF-8 bytes by default, as in Go. Graphemes are counted with
+// Intl.Segmenter, which implements UAX #29 in full and so can differ from
+// graphemeLen, e.g. for Hangul jamo or a CR LF pair.
+func jsLength(unit, x string) string {
+	switch unit {
+	case "runes":
+		return "[..." + x + "].length"
+	case "graphemes":
+		return "[...new Intl.Segmenter().segment(" + x + ")].length"
+	}
+	return "new TextEncoder().encode(" + x + ").length"
+}
+
+// jsUnsafeText matches a control or bidi formatting character.
+var jsUnsafeText = func() string {
+	var b strings.Builder
+	b.WriteString(`/[\p{Cc}`)
+	for _, r := range bidiControls {
+		fmt.Fprintf(&b, `\u%04x`, r)
+	}
+	return b.String() + "]/u"
+}()
+
+// jsLoneSurrogate matches the UTF-16 that has no UTF-8 encoding.
+const jsLoneSurrogate = `/\p{Cs}/u`
+
+// textCheck is a condition under which a value fails a rule, and the
+// message for it.
+type textCheck struct {
+	cond, msg string
+}
+
+// jsTextChecks returns the JavaScript checks on the string x for e's Unicode
+// rules and lengths. String.length counts UTF-16 units, which no LengthUnit
+// means, so lengths are always checked here.
+func jsTextChecks(e BoundaryEntry, x string) []textCheck {
+	var out []textCheck
+	if e.ValidUTF8 {
+		out = append(out, textCheck{jsLoneSurrogate + ".test(" + x + ")", "is not valid UTF-8"})
+	}
+	unit := lengthUnit(e)
+	n := jsLength(unit, x)
+	if e.MinLength > 0 {
+		out = append(out, textCheck{fmt.Sprintf("%s < %d", n, e.MinLength), fmt.Sprintf("is shorter than %d %s", e.MinLength, unit)})
+	}
+	if e.MaxLength > 0 {
+		out = append(out, textCheck{fmt.Sprintf("%s > %d", n, e.MaxLength), fmt.Sprintf("is longer than %d %s", e.MaxLength, unit)})
+	}
+	if e.NoControl {
+		out = append(out, textCheck{jsUnsafeText + ".test(" + x + ")", "contains control or bidi formatting characters"})
+	}
+	if e.NFC {
+		out = append(out, textCheck{x + `.normalize("NFC") !== ` + x, "is not NFC normalized"})
+	}
+	return out
+}
+
+// goTextHelpers declares the functions that generated text checks call.
+var goTextHelpers = map[string]string{
+	"graphemeLen": `
+// graphemeLen approximates the number of user-perceived characters in s
+// (UAX #29 grapheme clusters): combining marks, variation selectors, emoji
+// modifiers and characters joined by U+200D count with the character before
+// them, and regional indicators count in pairs.
+func graphemeLen(s string) int {
+	n, joined, flags := 0, false, 0
+	for _, r := range s {
+		attached := unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) ||
+			r >= 0xFE00 && r <= 0xFE0F || r >= 0x1F3FB && r <= 0x1F3FF
+		switch {
+		case r == 0x200D:
+			joined = true
+			continue
+		case r >= 0x1F1E6 && r <= 0x1F1FF:
+			flags++
+			attached = flags%2 == 0
+		default:
+			flags = 0
+		}
+		if n == 0 || !attached && !joined {
+			n++
+		}
+		joined = false
+	}
+	return n
+}
+`,
+	"unsafeRune": `
+// unsafeRune reports whether r is a control character or a bidirectional
+// formatting character, either of which can hide or reorder displayed text.
+func unsafeRune(r rune) bool {
+	return unicode.IsControl(r) || unicode.Is(unicode.Bidi_Control, r)
+}
+`,
+}
+
+// textHelperImports lists the imports each of goTextHelpers needs.
+var textHelperImports = map[string][]string{
+	"graphemeLen": {"unicode"},
+	"unsafeRune":  {"unicode"},
+}
+
+// unicodeSeeds are fuzz seeds for every string input: decomposed and
+// stacked combining marks, bidi overrides, zero-width and homoglyph
+// spoofing, emoji sequences whose byte, rune and grapheme lengths differ,
+// and invalid UTF-8 such as overlong encodings and encoded surrogates.
+var unicodeSeeds = []string{
+	"caf\u00E9", "cafe\u0301", "e\u0301\u0302\u0303\u0304\u0305\u0306\u0307\u0308\u0309\u030A",
+	"\u202Egnp.exe", "admin\u2066\u2069", "ad\u200Bmin", "\u0440\u0430ypal",
+	"\U0001F468\u200D\U0001F469\u200D\U0001F467", "\U0001F1FA\U0001F1F8", "\x00", "\x1b[2J",
+	"\xc0\xaf", "\xe0\x80\xaf", "\xed\xa0\x80", "\xff\xfe", "a\xc3",
+}
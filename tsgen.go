@@ -0,0 +1,332 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var jsIdentRe = regexp.MustCompile(`^[A-Za-z_$][\w$]*$`)
+
+// jsString quotes s as a JavaScript string literal.
+func jsString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// jsKey returns name as an object key, quoted unless it is an identifier.
+func jsKey(name string) string {
+	if jsIdentRe.MatchString(name) {
+		return name
+	}
+	return jsString(name)
+}
+
+// GenerateZod produces a module with one zod object schema per handler.
+// Numbers use z.coerce so that query and header strings parse, and booleans
+// must be exactly "true" or "false"; inside nested objects both are expected
+// already decoded from JSON. When typed is set the output is TypeScript and
+// also exports the inferred types.
+func GenerateZod(entries []BoundaryEntry, typed bool) string {
+	var s strings.Builder
+	s.WriteString("// Code generated by boundaryguard. DO NOT EDIT.\n\n")
+	s.WriteString("import { z } from \"zod\";\n")
+	for _, g := range groupEntries(entries) {
+		fmt.Fprintf(&s, "\nexport const %s = %s;\n", g.model, zodObject(g.entries, false, ""))
+		if typed {
+			fmt.Fprintf(&s, "export type %s = z.infer<typeof %s>;\n", g.model, g.model)
+		}
+	}
+	return s.String()
+}
+
+// zodObject returns a z.object schema with a property per entry, indented
+// by indent.
+func zodObject(entries []BoundaryEntry, decoded bool, indent string) string {
+	var s strings.Builder
+	s.WriteString("z.object({\n")
+	for _, e := range entries {
+		t := zodType(e, decoded, indent+"  ")
+		if !entryRequired(e) {
+			t += ".optional()"
+		}
+		fmt.Fprintf(&s, "%s  %s: %s,\n", indent, jsKey(e.ParamName), t)
+	}
+	return s.String() + indent + "})"
+}
+
+func zodType(e BoundaryEntry, decoded bool, indent string) string {
+	number := "z.coerce.number()"
+	if decoded {
+		number = "z.number()"
+	}
+	var t string
+	switch {
+	case e.DataType == "int":
+		t = number + ".int()"
+		if e.MinValue != 0 || e.MaxValue != 0 {
+			t += fmt.Sprintf(".min(%d).max(%d)", e.MinValue, e.MaxValue)
+		}
+	case e.DataType == "uint":
+		t = number + fmt.Sprintf(".int().min(%d)", max(e.MinValue, 0))
+		if e.MaxValue > 0 {
+			t += fmt.Sprintf(".max(%d)", e.MaxValue)
+		}
+	case e.DataType == "float64":
+		t = number + ".finite()"
+		if e.MinFloat != 0 || e.MaxFloat != 0 {
+			t += ".min(" + jsNumber(e.MinFloat) + ").max(" + jsNumber(e.MaxFloat) + ")"
+		}
+	case e.DataType == "bool" && decoded:
+		t = "z.boolean()"
+	case e.DataType == "bool":
+		t = `z.enum(["true", "false"])`
+	case e.DataType == "list":
+		item := "z.string()"
+		if e.Items != nil {
+			item = zodType(*e.Items, decoded, indent)
+		}
+		t = "z.array(" + item + ")"
+		if e.MinItems > 0 {
+			t += fmt.Sprintf(".min(%d)", e.MinItems)
+		}
+		if e.MaxItems > 0 {
+			t += fmt.Sprintf(".max(%d)", e.MaxItems)
+		}
+		if e.UniqueItems && (e.Items == nil || comparableType(e.Items.DataType)) {
+			t += `.refine((a) => new Set(a).size === a.length, "must not repeat items")`
+		}
+	case e.DataType == "object":
+		t = zodObject(e.Fields, true, indent)
+	case len(e.EnumValues) > 0:
+		vals := make([]string, len(e.EnumValues))
+		for i, v := range e.EnumValues {
+			vals[i] = jsString(v)
+		}
+		t = "z.enum([" + strings.Join(vals, ", ") + "])"
+	default:
+		t = "z.string()"
+		for _, p := range entryPatterns(e) {
+			t += ".regex(new RegExp(" + jsString(p.re) + "))"
+		}
+		// refine wraps the schema, so it comes after the string methods.
+		if e.DataType == "string" {
+			for _, c := range jsTextChecks(e, "s") {
+				t += ".refine((s) => !(" + c.cond + "), " + jsString(c.msg) + ")"
+			}
+		}
+	}
+	return t
+}
+
+// jsNumber formats f as a JavaScript number literal.
+func jsNumber(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// GenerateTSGuards produces dependency-free guard functions, one
+// is<Handler>Request per handler, that check an untrusted object against the
+// entries' constraints. As in GenerateZod, numbers at the top level may be
+// numeric strings, since query, path and header values always arrive as
+// strings, and booleans must be exactly "true" or "false"; inside nested
+// objects both are expected already decoded from JSON. Object entries get
+// guards of their own, written first and named after the model and field,
+// and list items are checked with the item's conditions. When typed is set
+// the output is TypeScript with an interface per model and type predicates;
+// otherwise it is plain JavaScript.
+func GenerateTSGuards(entries []BoundaryEntry, typed bool) string {
+	g := &tsGuardGen{typed: typed}
+	for _, grp := range groupEntries(entries) {
+		g.guard(grp.model, grp.entries, false)
+	}
+	out := "// Code generated by boundaryguard. DO NOT EDIT.\n"
+	if g.patterns.Len() > 0 {
+		out += "\n" + g.patterns.String()
+	}
+	return out + g.s.String()
+}
+
+type tsGuardGen struct {
+	s, patterns strings.Builder
+	typed       bool
+}
+
+// guard writes the interface and is<model> guard for entries, after the
+// guards of any nested objects. decoded is false for the top-level request,
+// whose numbers and booleans may still be strings.
+func (g *tsGuardGen) guard(model string, entries []BoundaryEntry, decoded bool) {
+	for _, e := range entries {
+		g.nested(e, model+camelName(e.ParamName))
+	}
+	s := &g.s
+	if g.typed {
+		fmt.Fprintf(s, "\nexport interface %s {\n", model)
+		for _, e := range entries {
+			opt := "?"
+			if entryRequired(e) {
+				opt = ""
+			}
+			fmt.Fprintf(s, "  %s%s: %s;\n", jsKey(e.ParamName), opt, tsType(e, model+camelName(e.ParamName), decoded))
+		}
+		s.WriteString("}\n")
+		fmt.Fprintf(s, "\nexport function is%s(input: unknown): input is %s {\n", model, model)
+	} else {
+		fmt.Fprintf(s, "\nexport function is%s(input) {\n", model)
+	}
+	s.WriteString("  if (typeof input !== \"object\" || input === null) return false;\n")
+	if g.typed {
+		s.WriteString("  const v = input as Record<string, unknown>;\n")
+	} else {
+		s.WriteString("  const v = input;\n")
+	}
+	for _, e := range entries {
+		fmt.Fprintf(s, "  {\n    const x = v[%s];\n", jsString(e.ParamName))
+		indent := "    "
+		if !entryRequired(e) {
+			s.WriteString("    if (x !== undefined) {\n")
+			indent = "      "
+		}
+		for _, check := range g.checks(e, model+camelName(e.ParamName), decoded) {
+			fmt.Fprintf(s, "%sif (%s) return false;\n", indent, check)
+		}
+		if !entryRequired(e) {
+			s.WriteString("    }\n")
+		}
+		s.WriteString("  }\n")
+	}
+	s.WriteString("  return true;\n}\n")
+}
+
+// nested writes the guards for the objects in e, named after name.
+func (g *tsGuardGen) nested(e BoundaryEntry, name string) {
+	switch {
+	case e.DataType == "object":
+		g.guard(name, e.Fields, true)
+	case e.DataType == "list" && e.Items != nil:
+		g.nested(*e.Items, name+"Item")
+	}
+}
+
+// checks returns the conditions under which x fails e, declaring e's
+// regexes at module level. name is the one passed to nested for e; decoded
+// is the one passed to guard.
+func (g *tsGuardGen) checks(e BoundaryEntry, name string, decoded bool) []string {
+	// A raw number is a number or a non-blank numeric string, compared after
+	// conversion as z.coerce.number() does.
+	n, isNum := "x", `typeof x !== "number"`
+	if !decoded {
+		n, isNum = "Number(x)", `typeof x !== "number" && (typeof x !== "string" || x.trim() === "")`
+	}
+	switch e.DataType {
+	case "int", "uint":
+		checks := []string{isNum + " || !Number.isInteger(" + n + ")"}
+		switch {
+		case e.DataType == "int" && (e.MinValue != 0 || e.MaxValue != 0):
+			checks = append(checks, fmt.Sprintf("%s < %d || %s > %d", n, e.MinValue, n, e.MaxValue))
+		case e.DataType == "uint":
+			checks = append(checks, fmt.Sprintf("%s < %d", n, max(e.MinValue, 0)))
+			if e.MaxValue > 0 {
+				checks = append(checks, fmt.Sprintf("%s > %d", n, e.MaxValue))
+			}
+		}
+		return checks
+	case "float64":
+		checks := []string{isNum + " || !Number.isFinite(" + n + ")"}
+		if e.MinFloat != 0 || e.MaxFloat != 0 {
+			checks = append(checks, n+" < "+jsNumber(e.MinFloat)+" || "+n+" > "+jsNumber(e.MaxFloat))
+		}
+		return checks
+	case "bool":
+		if !decoded {
+			return []string{`x !== "true" && x !== "false"`}
+		}
+		return []string{`typeof x !== "boolean"`}
+	case "list":
+		checks := []string{"!Array.isArray(x)"}
+		if e.MinItems > 0 {
+			checks = append(checks, fmt.Sprintf("x.length < %d", e.MinItems))
+		}
+		if e.MaxItems > 0 {
+			checks = append(checks, fmt.Sprintf("x.length > %d", e.MaxItems))
+		}
+		if e.UniqueItems && (e.Items == nil || comparableType(e.Items.DataType)) {
+			checks = append(checks, "new Set(x).size !== x.length")
+		}
+		if e.Items != nil {
+			// Each item shadows x, so its conditions apply unchanged.
+			item := g.checks(*e.Items, name+"Item", decoded)
+			for i, c := range item {
+				if len(item) > 1 {
+					item[i] = "(" + c + ")"
+				}
+			}
+			checks = append(checks, "x.some((x) => "+strings.Join(item, " || ")+")")
+		}
+		return checks
+	case "object":
+		return []string{"!is" + name + "(x)"}
+	}
+	checks := []string{`typeof x !== "string"`}
+	if len(e.EnumValues) > 0 {
+		vals := make([]string, len(e.EnumValues))
+		for i, v := range e.EnumValues {
+			vals[i] = jsString(v)
+		}
+		return append(checks, "!["+strings.Join(vals, ", ")+"].includes(x)")
+	}
+	if e.DataType == "string" {
+		for _, c := range jsTextChecks(e, "x") {
+			checks = append(checks, c.cond)
+		}
+	}
+	for _, p := range entryPatterns(e) {
+		re := lowerFirst(name) + p.suffix
+		fmt.Fprintf(&g.patterns, "const %s = new RegExp(%s);\n", re, jsString(p.re))
+		checks = append(checks, "!"+re+".test(x)")
+	}
+	return checks
+}
+
+// tsType returns the TypeScript type of e; name is the one passed to nested
+// for e and decoded the one passed to guard.
+func tsType(e BoundaryEntry, name string, decoded bool) string {
+	switch {
+	case e.DataType == "int" || e.DataType == "uint" || e.DataType == "float64":
+		if !decoded {
+			return "number | string"
+		}
+		return "number"
+	case e.DataType == "bool" && !decoded:
+		return `"true" | "false"`
+	case e.DataType == "bool":
+		return "boolean"
+	case e.DataType == "list":
+		item := "string"
+		if e.Items != nil {
+			item = tsType(*e.Items, name+"Item", decoded)
+		}
+		if strings.Contains(item, " | ") {
+			item = "(" + item + ")"
+		}
+		return item + "[]"
+	case e.DataType == "object":
+		return name
+	}
+	if len(e.EnumValues) > 0 {
+		vals := make([]string, len(e.EnumValues))
+		for i, v := range e.EnumValues {
+			vals[i] = jsString(v)
+		}
+		return strings.Join(vals, " | ")
+	}
+	return "string"
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
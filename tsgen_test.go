@@ -0,0 +1,128 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+var tsEntries = []BoundaryEntry{
+	{ParamName: "email", DataType: "string", MinLength: 1, MaxLength: 256, RegexPattern: `^\S+@\S+$`, Format: "none", Handler: "signup"},
+	{ParamName: "age", DataType: "int", MinValue: 13, MaxValue: 150, Handler: "signup"},
+	{ParamName: "X-Request-Id", DataType: "string", MaxLength: 64, Format: "none"},
+	{ParamName: "role", DataType: "enum", EnumValues: []string{"admin", "user"}},
+}
+
+func TestGenerateZod(t *testing.T) {
+	out := GenerateZod(tsEntries, true)
+	for _, want := range []string{
+		`import { z } from "zod";`,
+		"export const SignupRequest = z.object({\n",
+		// Lengths count UTF-8 bytes, as in the Go validators, not UTF-16 units.
+		`  email: z.string().regex(new RegExp("^\\S+@\\S+$")).refine((s) => !(new TextEncoder().encode(s).length < 1), "is shorter than 1 bytes")`,
+		"  age: z.coerce.number().int().min(13).max(150).optional(),",
+		`  "X-Request-Id": z.string().refine((s) => !(new TextEncoder().encode(s).length > 64), "is longer than 64 bytes").optional(),`,
+		`  role: z.enum(["admin", "user"]).optional(),`,
+		"export type SignupRequest = z.infer<typeof SignupRequest>;",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(GenerateZod(tsEntries, false), "export type") {
+		t.Error("JavaScript output should not export types")
+	}
+}
+
+func TestGenerateTSGuards(t *testing.T) {
+	out := GenerateTSGuards(tsEntries, true)
+	for _, want := range []string{
+		`const signupRequestEmailPattern = new RegExp("^\\S+@\\S+$");`,
+		"export interface SignupRequest {\n  email: string;\n  age?: number | string;\n}",
+		"export function isSignupRequest(input: unknown): input is SignupRequest {",
+		"    if (!signupRequestEmailPattern.test(x)) return false;",
+		"    if (new TextEncoder().encode(x).length > 256) return false;",
+		"      if (typeof x !== \"number\" && (typeof x !== \"string\" || x.trim() === \"\") || !Number.isInteger(Number(x))) return false;",
+		"      if (Number(x) < 13 || Number(x) > 150) return false;",
+		`  "X-Request-Id"?: string;`,
+		`  role?: "admin" | "user";`,
+		`      if (!["admin", "user"].includes(x)) return false;`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+	js := GenerateTSGuards(tsEntries, false)
+	if strings.Contains(js, "interface") || strings.Contains(js, "unknown") || !strings.Contains(js, "export function isSignupRequest(input) {") {
+		t.Errorf("JavaScript guards should carry no types:\n%s", js)
+	}
+}
+
+var tsNestedEntries = []BoundaryEntry{
+	{ParamName: "ids", DataType: "list", MinItems: 1, UniqueItems: true, Items: &BoundaryEntry{DataType: "uint", MaxValue: 1000}, Handler: "order"},
+	{ParamName: "gift", DataType: "bool", Handler: "order"},
+	{ParamName: "ship", DataType: "object", Handler: "order", Fields: []BoundaryEntry{
+		{ParamName: "weight", DataType: "float64", MinFloat: 0, MaxFloat: 70.5},
+		{ParamName: "fragile", DataType: "bool"},
+	}},
+}
+
+func TestGenerateZodNested(t *testing.T) {
+	out := GenerateZod(tsNestedEntries, false)
+	for _, want := range []string{
+		`  ids: z.array(z.coerce.number().int().min(0).max(1000)).min(1).refine((a) => new Set(a).size === a.length, "must not repeat items"),`,
+		`  gift: z.enum(["true", "false"]).optional(),`,
+		"  ship: z.object({\n    weight: z.number().finite().min(0).max(70.5).optional(),\n    fragile: z.boolean().optional(),\n  }).optional(),",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestGenerateTSGuardsNested(t *testing.T) {
+	out := GenerateTSGuards(tsNestedEntries, true)
+	for _, want := range []string{
+		"export interface OrderRequestShip {\n  weight?: number;\n  fragile?: boolean;\n}",
+		"export interface OrderRequest {\n  ids: (number | string)[];\n  gift?: \"true\" | \"false\";\n  ship?: OrderRequestShip;\n}",
+		"      if (typeof x !== \"boolean\") return false;",
+		"      if (x !== \"true\" && x !== \"false\") return false;",
+		"      if (typeof x !== \"number\" || !Number.isFinite(x)) return false;",
+		"    if (new Set(x).size !== x.length) return false;",
+		"    if (x.some((x) => (typeof x !== \"number\" && (typeof x !== \"string\" || x.trim() === \"\") || !Number.isInteger(Number(x))) || (Number(x) < 0) || (Number(x) > 1000))) return false;",
+		"      if (!isOrderRequestShip(x)) return false;",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "function isOrderRequestShip") > strings.Index(out, "function isOrderRequest(") {
+		t.Errorf("nested guard must come first:\n%s", out)
+	}
+}
+
+func TestGenerateTextChecks(t *testing.T) {
+	es := []BoundaryEntry{{ParamName: "name", DataType: "string", MinLength: 1, MaxLength: 20, LengthUnit: "graphemes", ValidUTF8: true, NFC: true, Format: "none"}}
+	zod := GenerateZod(es, false)
+	for _, want := range []string{
+		`name: z.string().refine((s) => !(/\p{Cs}/u.test(s)), "is not valid UTF-8")`,
+		`.refine((s) => !([...new Intl.Segmenter().segment(s)].length > 20), "is longer than 20 graphemes")`,
+		`.refine((s) => !(s.normalize("NFC") !== s), "is not NFC normalized"),`,
+	} {
+		if !strings.Contains(zod, want) {
+			t.Errorf("missing %q in:\n%s", want, zod)
+		}
+	}
+	if strings.Contains(zod, ".max(20)") {
+		t.Errorf("UTF-16 length check for a grapheme limit:\n%s", zod)
+	}
+	guards := GenerateTSGuards(es, true)
+	for _, want := range []string{
+		"    if (/\\p{Cs}/u.test(x)) return false;",
+		"    if ([...new Intl.Segmenter().segment(x)].length < 1) return false;",
+		`    if (x.normalize("NFC") !== x) return false;`,
+	} {
+		if !strings.Contains(guards, want) {
+			t.Errorf("missing %q in:\n%s", want, guards)
+		}
+	}
+}
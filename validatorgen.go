@@ -0,0 +1,577 @@
+package main
+
+import (
+	"fmt"
+	"go/format"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// GenerateValidators produces a complete, gofmt'd Go source file in package
+// pkg with one Validate<Param> function per entry. Entries that share a
+// Handler and File are also collected into a <Handler>Request struct checked
+// by a Validate<Handler> function; a handler name found in several files is
+// prefixed with each file's name, as in UsersCreateRequest. Failures are returned as *ValidationError, and
+// Validate<Handler> collects every failing field into ValidationErrors; both
+// types are declared in the generated file. Regex patterns are compiled once
+// at package level; an invalid pattern is reported as an error. Object
+// entries become structs with JSON tags, and failures inside lists and
+// objects carry their path, such as "user.tags[2]".
+func GenerateValidators(pkg string, entries []BoundaryEntry) (string, error) {
+	g := validatorGen{used: map[string]bool{}, imports: map[string]bool{"fmt": true, "strings": true}, helpers: map[string]bool{}}
+	files := map[string]map[string]bool{}
+	for _, e := range entries {
+		if files[e.Handler] == nil {
+			files[e.Handler] = map[string]bool{}
+		}
+		files[e.Handler][e.File] = true
+	}
+	var groups []*handlerGroup
+	byKey := map[[2]string]*handlerGroup{}
+	for _, e := range entries {
+		key := [2]string{e.File, e.Handler}
+		grp := byKey[key]
+		if grp == nil && e.Handler != "" {
+			grp = &handlerGroup{handler: e.Handler, file: e.File, name: e.Handler}
+			if len(files[e.Handler]) > 1 {
+				grp.name = strings.TrimSuffix(filepath.Base(e.File), filepath.Ext(e.File)) + "_" + e.Handler
+			}
+			byKey[key] = grp
+			groups = append(groups, grp)
+		}
+		if grp != nil {
+			e.Handler = grp.name
+		}
+		f, err := g.entry(e)
+		if err != nil {
+			return "", err
+		}
+		if grp != nil {
+			grp.fields = append(grp.fields, f)
+		}
+	}
+	for _, grp := range groups {
+		g.handler(grp)
+	}
+
+	if len(g.patterns) > 0 {
+		g.imports["regexp"] = true
+	}
+	var std, ext []string
+	for imp := range g.imports {
+		if first, _, _ := strings.Cut(imp, "/"); strings.Contains(first, ".") {
+			ext = append(ext, strconv.Quote(imp))
+		} else {
+			std = append(std, strconv.Quote(imp))
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(ext)
+
+	var src strings.Builder
+	fmt.Fprintln(&src, "// Code generated by boundaryguard. DO NOT EDIT.")
+	// Only the NFC rule reaches outside the standard library; say so where
+	// the build will fail without it.
+	for _, imp := range ext {
+		fmt.Fprintf(&src, "//\n// Requires %[1]s outside the standard library: go get %[1]s\n", strings.Trim(imp, `"`))
+	}
+	fmt.Fprintln(&src)
+	fmt.Fprintf(&src, "package %s\n", pkg)
+	imports := strings.Join(std, "\n")
+	if len(ext) > 0 {
+		imports += "\n\n" + strings.Join(ext, "\n")
+	}
+	fmt.Fprintf(&src, "\nimport (\n%s\n)\n", imports)
+	if len(g.patterns) > 0 {
+		fmt.Fprintf(&src, "\nvar (\n%s)\n", strings.Join(g.patterns, ""))
+	}
+	src.WriteString(validationErrorSource)
+	var helpers []string
+	for name := range g.helpers {
+		helpers = append(helpers, name)
+	}
+	sort.Strings(helpers)
+	for _, name := range helpers {
+		src.WriteString(goTextHelpers[name])
+	}
+	src.WriteString(g.funcs.String())
+
+	out, err := format.Source([]byte(src.String()))
+	if err != nil {
+		return "", fmt.Errorf("formatting generated validators: %w", err)
+	}
+	return string(out), nil
+}
+
+type validatorField struct {
+	name, typ, fn string
+}
+
+type validatorGen struct {
+	used     map[string]bool
+	imports  map[string]bool
+	helpers  map[string]bool
+	patterns []string
+	funcs    strings.Builder
+}
+
+// helper declares one of goTextHelpers in the generated file.
+func (g *validatorGen) helper(name string) {
+	g.helpers[name] = true
+	for _, imp := range textHelperImports[name] {
+		g.imports[imp] = true
+	}
+}
+
+// ident returns a Go identifier made of prefix and name that has not been
+// returned before.
+func (g *validatorGen) ident(prefix, name string) string {
+	base := prefix + camelName(name)
+	id := base
+	for i := 2; g.used[id]; i++ {
+		id = fmt.Sprintf("%s%d", base, i)
+	}
+	g.used[id] = true
+	return id
+}
+
+// entry writes the Validate function for e and returns the field it checks.
+func (g *validatorGen) entry(e BoundaryEntry) (validatorField, error) {
+	f := validatorField{name: fieldName(e.ParamName), fn: g.ident("Validate", e.Handler+"_"+e.ParamName)}
+	var err error
+	f.typ, err = g.validator(e, f.fn, e.ParamName, e.Handler+"_"+e.ParamName, false)
+	return f, err
+}
+
+// validator writes function fn checking e, reporting failures against field,
+// and returns the Go type fn takes. List items and object fields get their own
+// functions named after base, written first; their failures are prefixed
+// with the path to them. typed is set below an object, where JSON has already
+// decoded booleans.
+func (g *validatorGen) validator(e BoundaryEntry, fn, field, base string, typed bool) (string, error) {
+	switch e.DataType {
+	case "list":
+		return g.list(e, fn, field, base, typed)
+	case "object":
+		return g.object(e, fn, field, base)
+	}
+	typ := validatorType(e.DataType, typed)
+	w := &g.funcs
+	label := field
+	if label == "" {
+		label = "item"
+	}
+
+	fmt.Fprintf(w, "\n// %s checks %s against its discovered constraints.\n", fn, label)
+	fmt.Fprintf(w, "func %s(v %s) error {\n", fn, typ)
+	fail := func(rule, limit, got string) {
+		fmt.Fprintf(w, "\t\treturn &ValidationError{Field: %q, Rule: %q, Limit: %s, Got: %s}\n\t}\n",
+			field, rule, limit, got)
+	}
+	switch e.DataType {
+	case "int":
+		if e.MinValue != 0 || e.MaxValue != 0 {
+			fmt.Fprintf(w, "\tif v < %d {\n", e.MinValue)
+			fail("min", fmt.Sprintf("int64(%d)", e.MinValue), "v")
+			fmt.Fprintf(w, "\tif v > %d {\n", e.MaxValue)
+			fail("max", fmt.Sprintf("int64(%d)", e.MaxValue), "v")
+		}
+	case "uint":
+		if e.MinValue > 0 {
+			fmt.Fprintf(w, "\tif v < %d {\n", e.MinValue)
+			fail("min", fmt.Sprintf("uint64(%d)", e.MinValue), "v")
+		}
+		if e.MaxValue > 0 {
+			fmt.Fprintf(w, "\tif v > %d {\n", e.MaxValue)
+			fail("max", fmt.Sprintf("uint64(%d)", e.MaxValue), "v")
+		}
+	case "float64":
+		g.imports["math"] = true
+		fmt.Fprintf(w, "\tif math.IsNaN(v) || math.IsInf(v, 0) {\n")
+		fail("finite", "nil", "v")
+		if e.MinFloat != 0 || e.MaxFloat != 0 {
+			fmt.Fprintf(w, "\tif v < %s {\n", goFloatLit(e.MinFloat))
+			fail("min", goFloatLit(e.MinFloat), "v")
+			fmt.Fprintf(w, "\tif v > %s {\n", goFloatLit(e.MaxFloat))
+			fail("max", goFloatLit(e.MaxFloat), "v")
+		}
+	case "bool":
+		if typ == "string" {
+			fmt.Fprintf(w, "\tif v != \"true\" && v != \"false\" {\n")
+			fail("bool", `[]string{"true", "false"}`, "v")
+		}
+	default:
+		if err := checkLengthUnit(e, label); err != nil {
+			return typ, err
+		}
+		if e.ValidUTF8 {
+			g.imports["unicode/utf8"] = true
+			fmt.Fprintf(w, "\tif !utf8.ValidString(v) {\n")
+			fail("utf8", "nil", "v")
+		}
+		n := goLength(e.LengthUnit, "v")
+		if e.MaxLength > 0 || e.MinLength > 0 {
+			switch e.LengthUnit {
+			case "runes":
+				g.imports["unicode/utf8"] = true
+			case "graphemes":
+				g.helper("graphemeLen")
+			}
+		}
+		if e.MaxLength > 0 {
+			fmt.Fprintf(w, "\tif %s > %d {\n", n, e.MaxLength)
+			fail("max_length", fmt.Sprint(e.MaxLength), n)
+		}
+		if e.MinLength > 0 {
+			fmt.Fprintf(w, "\tif %s < %d {\n", n, e.MinLength)
+			fail("min_length", fmt.Sprint(e.MinLength), n)
+		}
+		if e.NoControl {
+			g.helper("unsafeRune")
+			fmt.Fprintf(w, "\tif %s {\n", goUnsafeText("v"))
+			fail("control_chars", "nil", "v")
+		}
+		if e.NFC {
+			g.imports["golang.org/x/text/unicode/norm"] = true
+			fmt.Fprintf(w, "\tif !norm.NFC.IsNormalString(v) {\n")
+			fail("nfc", "nil", "v")
+		}
+	}
+	if len(e.EnumValues) > 0 && typ == "string" {
+		quoted := make([]string, len(e.EnumValues))
+		for i, v := range e.EnumValues {
+			quoted[i] = fmt.Sprintf("%q", v)
+		}
+		cases := strings.Join(quoted, ", ")
+		fmt.Fprintf(w, "\tswitch v {\n\tcase %s:\n\tdefault:\n", cases)
+		fail("enum", "[]string{"+cases+"}", "v")
+	}
+	if e.RegexPattern != "" && typ == "string" {
+		if _, err := regexp.Compile(e.RegexPattern); err != nil {
+			return typ, fmt.Errorf("%s: %w", label, err)
+		}
+		re := g.ident("pattern", base)
+		re = strings.ToLower(re[:1]) + re[1:]
+		g.patterns = append(g.patterns, fmt.Sprintf("\t%s = regexp.MustCompile(%s)\n", re, goStringLit(e.RegexPattern)))
+		fmt.Fprintf(w, "\tif !%s.MatchString(v) {\n", re)
+		fail("regex", re+".String()", "v")
+	}
+	if format := entryFormat(e); format != "" && typ == "string" {
+		spec, ok := formats[format]
+		if !ok {
+			return typ, fmt.Errorf("%s: unknown format %q", label, format)
+		}
+		for _, imp := range spec.imports {
+			g.imports[imp] = true
+		}
+		re := ""
+		if formatUsesPattern(format) {
+			re = "format" + camelName(format)
+			if !g.used[re] {
+				g.used[re] = true
+				g.patterns = append(g.patterns, fmt.Sprintf("\t%s = regexp.MustCompile(%s)\n",
+					re, goStringLit(formatPattern(format, entrySchemes(e)))))
+			}
+		}
+		fmt.Fprintf(w, "\tif %s {\n", formatCondition(e, "v", re))
+		fail("format", strconv.Quote(format), "v")
+	}
+	fmt.Fprintf(w, "\treturn nil\n}\n")
+	return typ, nil
+}
+
+// list writes the validator for a list entry: item count and uniqueness are
+// checked first, then every item is checked and all failures collected.
+func (g *validatorGen) list(e BoundaryEntry, fn, field, base string, typed bool) (string, error) {
+	itemTyp, itemFn := "string", ""
+	if e.Items != nil {
+		itemFn = g.ident("Validate", base+"_Item")
+		var err error
+		if itemTyp, err = g.validator(*e.Items, itemFn, "", base+"_Item", typed); err != nil {
+			return "", err
+		}
+	}
+	typ := "[]" + itemTyp
+	w := &g.funcs
+	fmt.Fprintf(w, "\n// %s checks %s and each of its items.\n", fn, field)
+	fmt.Fprintf(w, "func %s(v %s) error {\n", fn, typ)
+	if e.MinItems > 0 {
+		fmt.Fprintf(w, "\tif len(v) < %d {\n\t\treturn &ValidationError{Field: %q, Rule: \"min_items\", Limit: %d, Got: len(v)}\n\t}\n",
+			e.MinItems, field, e.MinItems)
+	}
+	if e.MaxItems > 0 {
+		fmt.Fprintf(w, "\tif len(v) > %d {\n\t\treturn &ValidationError{Field: %q, Rule: \"max_items\", Limit: %d, Got: len(v)}\n\t}\n",
+			e.MaxItems, field, e.MaxItems)
+	}
+	path := fmt.Sprintf("fmt.Sprintf(\"%s[%%d]\", i)", field)
+	if e.UniqueItems && (e.Items == nil || comparableType(e.Items.DataType)) {
+		fmt.Fprintf(w, "\tseen := make(map[%s]bool, len(v))\n\tfor i, item := range v {\n", itemTyp)
+		fmt.Fprintf(w, "\t\tif seen[item] {\n\t\t\treturn &ValidationError{Field: %s, Rule: \"unique\", Got: item}\n\t\t}\n", path)
+		fmt.Fprintf(w, "\t\tseen[item] = true\n\t}\n")
+	}
+	if itemFn == "" {
+		fmt.Fprintf(w, "\treturn nil\n}\n")
+		return typ, nil
+	}
+	fmt.Fprintf(w, "\tvar errs ValidationErrors\n\tfor i, item := range v {\n")
+	fmt.Fprintf(w, "\t\terrs.add(withPath(%s(item), %s))\n\t}\n\treturn errs.orNil()\n}\n", itemFn, path)
+	return typ, nil
+}
+
+// object writes a struct with JSON tags for an object entry and a validator
+// that checks every field and collects all failures under field.
+func (g *validatorGen) object(e BoundaryEntry, fn, field, base string) (string, error) {
+	fields := make([]validatorField, len(e.Fields))
+	for i, m := range e.Fields {
+		fields[i] = validatorField{name: fieldName(m.ParamName), fn: g.ident("Validate", base+"_"+m.ParamName)}
+		var err error
+		if fields[i].typ, err = g.validator(m, fields[i].fn, m.ParamName, base+"_"+m.ParamName, true); err != nil {
+			return "", err
+		}
+	}
+	typ := g.ident("", base)
+	w := &g.funcs
+	fmt.Fprintf(w, "\n// %s is the JSON object checked by %s.\ntype %s struct {\n", typ, fn, typ)
+	seen := map[string]bool{}
+	for i, f := range fields {
+		for n := 2; seen[fields[i].name]; n++ {
+			fields[i].name = fmt.Sprintf("%s%d", f.name, n)
+		}
+		seen[fields[i].name] = true
+		fmt.Fprintf(w, "\t%s %s `json:%q`\n", fields[i].name, f.typ, e.Fields[i].ParamName+",omitempty")
+	}
+	label := field
+	if label == "" {
+		label = "item"
+	}
+	fmt.Fprintf(w, "}\n\n// %s checks every field of %s and returns all failures\n// as ValidationErrors.\n", fn, label)
+	fmt.Fprintf(w, "func %s(v %s) error {\n\tvar errs ValidationErrors\n", fn, typ)
+	for _, f := range fields {
+		fmt.Fprintf(w, "\terrs.add(withPath(%s(v.%s), %q))\n", f.fn, f.name, field)
+	}
+	fmt.Fprintf(w, "\treturn errs.orNil()\n}\n")
+	return typ, nil
+}
+
+// handlerGroup is the entries one handler in one file reads. name is the
+// handler's name in generated identifiers.
+type handlerGroup struct {
+	handler, file, name string
+	fields              []validatorField
+}
+
+// handler writes the request struct and Validate function for one handler.
+func (g *validatorGen) handler(grp *handlerGroup) {
+	name, fields := grp.handler, grp.fields
+	if grp.name != grp.handler {
+		name += " in " + filepath.Base(grp.file)
+	}
+	typ := g.ident("", grp.name+"_Request")
+	fn := g.ident("Validate", grp.name)
+	w := &g.funcs
+	fmt.Fprintf(w, "\n// %s holds the inputs read by %s.\ntype %s struct {\n", typ, name, typ)
+	seen := map[string]bool{}
+	for i, f := range fields {
+		for n := 2; seen[fields[i].name]; n++ {
+			fields[i].name = fmt.Sprintf("%s%d", f.name, n)
+		}
+		seen[fields[i].name] = true
+		fmt.Fprintf(w, "\t%s %s\n", fields[i].name, f.typ)
+	}
+	fmt.Fprintf(w, "}\n\n// %s checks every input read by %s and returns all failures\n// as ValidationErrors.\n", fn, name)
+	fmt.Fprintf(w, "func %s(req *%s) error {\n\tvar errs ValidationErrors\n", fn, typ)
+	for _, f := range fields {
+		fmt.Fprintf(w, "\terrs.add(%s(req.%s))\n", f.fn, f.name)
+	}
+	fmt.Fprintf(w, "\treturn errs.orNil()\n}\n")
+}
+
+// fieldName returns an exported struct field name for param.
+func fieldName(param string) string {
+	n := camelName(param)
+	if n == "" || !unicode.IsLetter(rune(n[0])) {
+		n = "P" + n
+	}
+	return n
+}
+
+// validatorType maps a scalar BoundaryEntry DataType to the Go parameter
+// type. Booleans stay strings for strict parsing unless typed, i.e. decoded
+// from a JSON object.
+func validatorType(dataType string, typed bool) string {
+	switch dataType {
+	case "int":
+		return "int64"
+	case "uint":
+		return "uint64"
+	case "float64":
+		return "float64"
+	case "bool":
+		if typed {
+			return "bool"
+		}
+	}
+	return "string"
+}
+
+// goStringLit quotes s as a raw string literal when it can, which keeps
+// regex patterns readable.
+func goStringLit(s string) string {
+	if strings.ContainsAny(s, "`\r") {
+		return fmt.Sprintf("%q", s)
+	}
+	return "`" + s + "`"
+}
+
+// validationErrorSource declares the error types returned by generated
+// validators.
+const validationErrorSource = `
+// ValidationError describes one input that failed a rule. Limit is the
+// bound or allowed set the rule enforces and Got is what was received: a
+// length for length rules, otherwise the value itself.
+type ValidationError struct {
+	Field string ` + "`json:\"field\"`" + `
+	Rule  string ` + "`json:\"rule\"`" + `
+	Limit any    ` + "`json:\"limit\"`" + `
+	Got   any    ` + "`json:\"got\"`" + `
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: fails %s (limit %v, got %v)", e.Field, e.Rule, e.Limit, e.Got)
+}
+
+// ValidationErrors collects every failure found in one request.
+type ValidationErrors []*ValidationError
+
+func (es ValidationErrors) Error() string {
+	msgs := make([]string, len(es))
+	for i, e := range es {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap lets errors.As find the individual failures.
+func (es ValidationErrors) Unwrap() []error {
+	out := make([]error, len(es))
+	for i, e := range es {
+		out[i] = e
+	}
+	return out
+}
+
+func (es *ValidationErrors) add(err error) {
+	switch err := err.(type) {
+	case *ValidationError:
+		*es = append(*es, err)
+	case ValidationErrors:
+		*es = append(*es, err...)
+	}
+}
+
+// withPath prefixes the Field of every failure in err with path, so errors
+// from list items and object fields name where they were found.
+func withPath(err error, path string) error {
+	switch err := err.(type) {
+	case *ValidationError:
+		err.Field = joinPath(path, err.Field)
+	case ValidationErrors:
+		for _, e := range err {
+			e.Field = joinPath(path, e.Field)
+		}
+	}
+	return err
+}
+
+func joinPath(path, field string) string {
+	switch {
+	case path == "":
+		return field
+	case field == "" || strings.HasPrefix(field, "["):
+		return path + field
+	}
+	return path + "." + field
+}
+
+func (es ValidationErrors) orNil() error {
+	if len(es) == 0 {
+		return nil
+	}
+	return es
+}
+`
+
+// goValidatorsPkg is the package GenerateAlongside writes into a
+// subdirectory of each Go package directory. A package of its own keeps the
+// generated ValidationError, ValidationErrors and helpers from colliding
+// with names the scanned package already declares.
+const goValidatorsPkg = "boundaryvalidate"
+
+// GenerateAlongside generates validators for entries in the language of the
+// file each was found in, and returns their sources keyed by the path to
+// write them to, next to the scanned code:
+//
+//   - Go: one boundaryvalidate/validators.go per package directory, to be
+//     imported by the handlers
+//   - Python: <name>_validators.py with Pydantic models
+//   - JS/TS: <name>.validators.ts (or .js) with zod schemas, or guard
+//     functions when tsStyle is "guard"
+//
+// Entries without a File or from other languages are skipped.
+func GenerateAlongside(entries []BoundaryEntry, tsStyle string) (map[string]string, error) {
+	if tsStyle != "zod" && tsStyle != "guard" {
+		return nil, fmt.Errorf("unknown TypeScript validator style %q (want zod or guard)", tsStyle)
+	}
+	byFile := map[string][]BoundaryEntry{}
+	goDirs := map[string][]BoundaryEntry{}
+	var goFiles []string
+	for _, e := range entries {
+		if e.File == "" {
+			continue
+		}
+		if fileLanguage(e.File) == "Go" {
+			dir := filepath.Dir(e.File)
+			if goDirs[dir] == nil {
+				goFiles = append(goFiles, e.File)
+			}
+			goDirs[dir] = append(goDirs[dir], e)
+			continue
+		}
+		byFile[e.File] = append(byFile[e.File], e)
+	}
+
+	out := map[string]string{}
+	sort.Strings(goFiles)
+	for _, f := range goFiles {
+		dir := filepath.Dir(f)
+		src, err := GenerateValidators(goValidatorsPkg, goDirs[dir])
+		if err != nil {
+			return nil, err
+		}
+		out[filepath.Join(dir, goValidatorsPkg, "validators.go")] = src
+	}
+	for f, es := range byFile {
+		ext := strings.ToLower(filepath.Ext(f))
+		base := strings.TrimSuffix(f, filepath.Ext(f))
+		switch fileLanguage(f) {
+		case "Python":
+			out[base+"_validators.py"] = GeneratePydantic(es)
+		case "JavaScript", "Vue", "Svelte":
+			typed := ext != ".js" && ext != ".jsx" && ext != ".mjs" && ext != ".cjs"
+			name := base + ".validators.js"
+			if typed {
+				name = base + ".validators.ts"
+			}
+			if tsStyle == "guard" {
+				out[name] = GenerateTSGuards(es, typed)
+			} else {
+				out[name] = GenerateZod(es, typed)
+			}
+		}
+	}
+	return out, nil
+}
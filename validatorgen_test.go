@@ -0,0 +1,296 @@
+package main
+
+import (
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// typeCheck parses and type-checks src, failing the test on any error,
+// including unused imports.
+func typeCheck(t *testing.T, src string) {
+	t.Helper()
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "validate.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("generated code does not parse: %v\n%s", err, src)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err := conf.Check("validate", fset, []*ast.File{f}, nil); err != nil {
+		t.Fatalf("generated code does not compile: %v\n%s", err, src)
+	}
+}
+
+func TestGenerateValidators(t *testing.T) {
+	entries := []BoundaryEntry{
+		{ParamName: "email", DataType: "string", MaxLength: 256, RegexPattern: `^[^@]+@[^@]+$`, Handler: "createUser"},
+		{ParamName: "age", DataType: "int", MinValue: 0, MaxValue: 150, Handler: "createUser"},
+		{ParamName: "X-Request-Id", DataType: "string", MaxLength: 64},
+		{ParamName: "role", DataType: "enum", EnumValues: []string{"admin", "user"}},
+		{ParamName: "email", DataType: "string", MaxLength: 100, Handler: "createUser"},
+	}
+	src, err := GenerateValidators("validate", entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	typeCheck(t, src)
+	if formatted, _ := format.Source([]byte(src)); string(formatted) != src {
+		t.Error("output is not gofmt'd")
+	}
+	for _, want := range []string{
+		"func ValidateCreateUserEmail(v string) error",
+		"func ValidateCreateUserAge(v int64) error",
+		"func ValidateXRequestId(v string) error",
+		"func ValidateRole(v string) error",
+		"func ValidateCreateUser(req *CreateUserRequest) error",
+		"patternCreateUserEmail = regexp.MustCompile(`^[^@]+@[^@]+$`)",
+		"Email2 string",
+		"ValidateCreateUserEmail2(req.Email2)",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("missing %q in:\n%s", want, src)
+		}
+	}
+	if strings.Contains(src, "regexp.MatchString") {
+		t.Error("patterns should be compiled once at package level")
+	}
+}
+
+func TestGenerateValidatorsHandlerPerFile(t *testing.T) {
+	src, err := GenerateValidators("validate", []BoundaryEntry{
+		{ParamName: "email", DataType: "string", MaxLength: 256, Handler: "create", File: "api/users.go"},
+		{ParamName: "sku", DataType: "string", MaxLength: 32, Handler: "create", File: "api/orders.go"},
+		{ParamName: "name", DataType: "string", MaxLength: 64, Handler: "create", File: "api/users.go"},
+		{ParamName: "q", DataType: "string", MaxLength: 64, Handler: "search", File: "api/users.go"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	typeCheck(t, src)
+	for _, want := range []string{
+		"// UsersCreateRequest holds the inputs read by create in users.go.\ntype UsersCreateRequest struct {\n\tEmail string\n\tName  string\n}",
+		"// OrdersCreateRequest holds the inputs read by create in orders.go.\ntype OrdersCreateRequest struct {\n\tSku string\n}",
+		"func ValidateUsersCreate(req *UsersCreateRequest) error",
+		"func ValidateOrdersCreate(req *OrdersCreateRequest) error",
+		"func ValidateOrdersCreateSku(v string) error",
+		"func ValidateSearch(req *SearchRequest) error",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("missing %q in:\n%s", want, src)
+		}
+	}
+}
+
+func TestGenerateValidatorsTypedErrors(t *testing.T) {
+	entries := []BoundaryEntry{
+		{ParamName: "name", DataType: "string", MaxLength: 64, Handler: "h"},
+		{ParamName: "page", DataType: "int", MinValue: 1, MaxValue: 100, Handler: "h"},
+		{ParamName: "sort", DataType: "enum", EnumValues: []string{"asc", "desc"}, Handler: "h"},
+	}
+	src, err := GenerateValidators("validate", entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	typeCheck(t, src)
+	for _, want := range []string{
+		"type ValidationError struct",
+		"type ValidationErrors []*ValidationError",
+		`return &ValidationError{Field: "name", Rule: "max_length", Limit: 64, Got: len(v)}`,
+		`return &ValidationError{Field: "page", Rule: "min", Limit: int64(1), Got: v}`,
+		`return &ValidationError{Field: "page", Rule: "max", Limit: int64(100), Got: v}`,
+		`return &ValidationError{Field: "sort", Rule: "enum", Limit: []string{"asc", "desc"}, Got: v}`,
+		"errs.add(ValidateHPage(req.Page))",
+		"return errs.orNil()",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("missing %q in:\n%s", want, src)
+		}
+	}
+}
+
+func TestGenerateValidatorsImports(t *testing.T) {
+	src, err := GenerateValidators("validate", []BoundaryEntry{{ParamName: "q", DataType: "string"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	typeCheck(t, src)
+	if strings.Contains(src, `"regexp"`) {
+		t.Errorf("regexp imported without patterns:\n%s", src)
+	}
+}
+
+func TestGenerateValidatorsBadPattern(t *testing.T) {
+	_, err := GenerateValidators("validate", []BoundaryEntry{{ParamName: "q", DataType: "string", RegexPattern: "("}})
+	if err == nil || !strings.Contains(err.Error(), "q:") {
+		t.Fatalf("want an error naming the param, got %v", err)
+	}
+}
+
+func TestGenerateValidatorsNested(t *testing.T) {
+	addr := BoundaryEntry{ParamName: "address", DataType: "object", Fields: []BoundaryEntry{
+		{ParamName: "zip", DataType: "uint", MaxValue: 99999},
+		{ParamName: "lat", DataType: "float64", MinFloat: -90, MaxFloat: 90},
+		{ParamName: "verified", DataType: "bool"},
+	}}
+	src, err := GenerateValidators("validate", []BoundaryEntry{
+		{ParamName: "tags", DataType: "list", MinItems: 1, MaxItems: 5, UniqueItems: true,
+			Items: &BoundaryEntry{DataType: "string", MaxLength: 8}, Handler: "h"},
+		{ParamName: "notify", DataType: "bool", Handler: "h"},
+		{ParamName: "profile", DataType: "object", Handler: "h", Fields: []BoundaryEntry{
+			{ParamName: "addresses", DataType: "list", MaxItems: 2, Items: &addr},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	typeCheck(t, src)
+	for _, want := range []string{
+		"func ValidateHTagsItem(v string) error",
+		"func ValidateHTags(v []string) error",
+		`return &ValidationError{Field: "tags", Rule: "min_items", Limit: 1, Got: len(v)}`,
+		`return &ValidationError{Field: fmt.Sprintf("tags[%d]", i), Rule: "unique", Got: item}`,
+		`errs.add(withPath(ValidateHTagsItem(item), fmt.Sprintf("tags[%d]", i)))`,
+		`if v != "true" && v != "false" {`,
+		"func ValidateHProfileAddressesItemVerified(v bool) error",
+		"if math.IsNaN(v) || math.IsInf(v, 0) {",
+		"Zip      uint64  `json:\"zip,omitempty\"`",
+		"func ValidateHProfileAddresses(v []HProfileAddressesItem) error",
+		`errs.add(withPath(ValidateHProfileAddresses(v.Addresses), "profile"))`,
+		"Profile HProfile",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("missing %q in:\n%s", want, src)
+		}
+	}
+}
+
+func TestEntriesFromBoundaries(t *testing.T) {
+	bs := []Boundary{
+		{Function: "h", Variable: "q"},
+		{Function: "h", Variable: "q"},
+		{Function: "h", Variable: "page", DataType: "i64"},
+		{Function: "h", Variable: "ids", DataType: "[]uint32"},
+		{Function: "h", Variable: "ratio", DataType: "Option<f64>"},
+		{Function: "h", Variable: "items", DataType: "List[Item]"},
+	}
+	es := EntriesFromBoundaries(bs)
+	if len(es) != 5 {
+		t.Fatalf("want duplicates merged into 5 entries, got %d", len(es))
+	}
+	if es[2].DataType != "list" || es[2].Items == nil || es[2].Items.DataType != "uint" {
+		t.Errorf("bad list entry: %+v", es[2])
+	}
+	if es[3].DataType != "float64" {
+		t.Errorf("bad float entry: %+v", es[3])
+	}
+	if es[4].DataType != "list" || es[4].Items != nil {
+		t.Errorf("unknown item types should get no item rule: %+v", es[4])
+	}
+	if es[0].DataType != "string" || es[0].MaxLength != 1024 || es[0].Handler != "h" {
+		t.Errorf("bad string entry: %+v", es[0])
+	}
+	if es[1].DataType != "int" {
+		t.Errorf("bad int entry: %+v", es[1])
+	}
+}
+
+func TestEntriesFromBoundariesOptional(t *testing.T) {
+	var bs []Boundary
+	for _, typ := range []string{"Option<String>", "Optional[str]", "str | None", "*string", "string?"} {
+		bs = append(bs, Boundary{Function: "h", Variable: typ, DataType: typ})
+	}
+	bs = append(bs, Boundary{Function: "h", Variable: "bio", DataType: "string", Optional: true})
+	for _, e := range EntriesFromBoundaries(bs) {
+		if e.DataType != "string" || e.MaxLength != 1024 || entryRequired(e) {
+			t.Errorf("%s: want an optional string, got %+v", e.ParamName, e)
+		}
+	}
+	if e := EntriesFromBoundaries([]Boundary{{Variable: "name", DataType: "String"}})[0]; !entryRequired(e) {
+		t.Errorf("plain strings stay required: %+v", e)
+	}
+}
+
+func TestGenerateAlongside(t *testing.T) {
+	dir := t.TempDir()
+	goFile := filepath.Join(dir, "api", "h.go")
+	mustWrite(t, goFile, "package api\n")
+	entries := []BoundaryEntry{
+		{ParamName: "q", DataType: "string", MaxLength: 10, Handler: "h", File: goFile},
+		{ParamName: "q", DataType: "string", MaxLength: 10, Handler: "search", File: filepath.Join(dir, "app.py")},
+		{ParamName: "id", DataType: "string", MaxLength: 10, Handler: "get", File: filepath.Join(dir, "web", "h.ts")},
+		{ParamName: "id", DataType: "string", MaxLength: 10, Handler: "get", File: filepath.Join(dir, "web", "old.js")},
+		{ParamName: "id", DataType: "string", MaxLength: 10, File: filepath.Join(dir, "x.rb")},
+		{ParamName: "PORT", DataType: "int"},
+	}
+	files, err := GenerateAlongside(entries, "guard")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		filepath.Join(dir, "api", goValidatorsPkg, "validators.go"): "package boundaryvalidate\n",
+		filepath.Join(dir, "app_validators.py"):                     "class SearchRequest(BaseModel):",
+		filepath.Join(dir, "web", "h.validators.ts"):                "export function isGetRequest(input: unknown)",
+		filepath.Join(dir, "web", "old.validators.js"):              "export function isGetRequest(input) {",
+	}
+	if len(files) != len(want) {
+		t.Errorf("want %d files, got %d: %v", len(want), len(files), files)
+	}
+	for path, w := range want {
+		if !strings.Contains(files[path], w) {
+			t.Errorf("%s: missing %q in:\n%s", path, w, files[path])
+		}
+	}
+	typeCheck(t, files[filepath.Join(dir, "api", goValidatorsPkg, "validators.go")])
+	if _, err := GenerateAlongside(entries, "yup"); err == nil {
+		t.Error("want an error for an unknown TypeScript style")
+	}
+}
+
+func TestGenerateValidatorsText(t *testing.T) {
+	src, err := GenerateValidators("validate", []BoundaryEntry{
+		{ParamName: "name", DataType: "string", MinLength: 1, MaxLength: 32, LengthUnit: "graphemes", ValidUTF8: true, NoControl: true},
+		{ParamName: "nick", DataType: "string", MaxLength: 16, LengthUnit: "runes"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	typeCheck(t, src)
+	for _, want := range []string{
+		"if !utf8.ValidString(v) {",
+		"if graphemeLen(v) > 32 {",
+		`return &ValidationError{Field: "name", Rule: "max_length", Limit: 32, Got: graphemeLen(v)}`,
+		"if strings.IndexFunc(v, unsafeRune) >= 0 {",
+		"func graphemeLen(s string) int {",
+		"func unsafeRune(r rune) bool {",
+		"if utf8.RuneCountInString(v) > 16 {",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("missing %q in:\n%s", want, src)
+		}
+	}
+
+	src, err = GenerateValidators("validate", []BoundaryEntry{{ParamName: "q", DataType: "string", NFC: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// norm is not in the standard library, so only check the import block.
+	if !strings.Contains(src, "\"strings\"\n\n\t\"golang.org/x/text/unicode/norm\"\n)") {
+		t.Errorf("third-party import should be grouped last:\n%s", src)
+	}
+	if !strings.Contains(src, "// Requires golang.org/x/text/unicode/norm outside the standard library: go get golang.org/x/text/unicode/norm\n") {
+		t.Errorf("the header should name the norm dependency:\n%s", src)
+	}
+	if !strings.Contains(src, "if !norm.NFC.IsNormalString(v) {") || strings.Contains(src, "func graphemeLen") {
+		t.Errorf("bad NFC validator:\n%s", src)
+	}
+
+	if _, err := GenerateValidators("validate", []BoundaryEntry{{ParamName: "q", DataType: "string", LengthUnit: "chars"}}); err == nil {
+		t.Error("want an error for an unknown length unit")
+	}
+}
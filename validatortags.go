@@ -0,0 +1,286 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ValidatorTag returns the go-playground/validator tag for e, such as
+// "max=64,min=1", "oneof=a b c" or "gte=1,lte=100". Regex patterns, and the
+// formats without a built-in validator, are left out; "" means e has no
+// constraint the tag language can express. Lists get their item count and
+// uniqueness, then "dive" and the item's tag; nested objects are validated
+// through their own struct's tags, and booleans need none once decoded.
+func ValidatorTag(e BoundaryEntry) string {
+	var parts []string
+	switch e.DataType {
+	case "int":
+		if e.MinValue != 0 || e.MaxValue != 0 {
+			parts = append(parts, fmt.Sprintf("gte=%d", e.MinValue), fmt.Sprintf("lte=%d", e.MaxValue))
+		}
+	case "uint":
+		if e.MinValue > 0 {
+			parts = append(parts, fmt.Sprintf("gte=%d", e.MinValue))
+		}
+		if e.MaxValue > 0 {
+			parts = append(parts, fmt.Sprintf("lte=%d", e.MaxValue))
+		}
+	case "float64":
+		if e.MinFloat != 0 || e.MaxFloat != 0 {
+			parts = append(parts, "gte="+strconv.FormatFloat(e.MinFloat, 'g', -1, 64),
+				"lte="+strconv.FormatFloat(e.MaxFloat, 'g', -1, 64))
+		}
+	case "bool", "object":
+		return ""
+	case "list":
+		if e.MaxItems > 0 {
+			parts = append(parts, fmt.Sprintf("max=%d", e.MaxItems))
+		}
+		if e.MinItems > 0 {
+			parts = append(parts, fmt.Sprintf("min=%d", e.MinItems))
+		}
+		if e.UniqueItems {
+			parts = append(parts, "unique")
+		}
+		if e.Items != nil {
+			if tag := ValidatorTag(*e.Items); tag != "" {
+				parts = append(parts, "dive", tag)
+			}
+		}
+		return strings.Join(parts, ",")
+	default:
+		if e.MaxLength > 0 {
+			parts = append(parts, fmt.Sprintf("max=%d", e.MaxLength))
+		}
+		if e.MinLength > 0 {
+			parts = append(parts, fmt.Sprintf("min=%d", e.MinLength))
+		}
+	}
+	if tag := formats[entryFormat(e)].tag; tag != "" {
+		parts = append(parts, tag)
+	}
+	if len(e.EnumValues) > 0 {
+		vals := make([]string, len(e.EnumValues))
+		for i, v := range e.EnumValues {
+			vals[i] = oneofValue(v)
+		}
+		parts = append(parts, "oneof="+strings.Join(vals, " "))
+	}
+	return strings.Join(parts, ",")
+}
+
+// oneofValue escapes v for a oneof list: the validator splits the tag on
+// commas and pipes, and values containing spaces must be single-quoted.
+func oneofValue(v string) string {
+	v = strings.NewReplacer(",", "0x2C", "|", "0x7C").Replace(v)
+	if strings.ContainsAny(v, " '") {
+		return "'" + v + "'"
+	}
+	return v
+}
+
+// ValidatorTagPatch adds validate tags to the Go struct fields the scanner
+// reported in bs and returns the edits as a unified diff for review. That is
+// the fields of config structs, and those of the request structs handlers
+// decode or bind, wherever in the package they are declared. Each boundary
+// takes its tag from the entry with the same Handler and ParamName as its
+// Function and Variable. Fields that already carry a validate tag, and
+// fields whose type the tag cannot describe, are left alone.
+func ValidatorTagPatch(bs []Boundary, entries []BoundaryEntry) (string, error) {
+	tags := map[[2]string]string{}
+	for _, e := range entries {
+		tags[[2]string{e.Handler, e.ParamName}] = ValidatorTag(e)
+	}
+	type pos struct{ line, col int }
+	byPos := map[string]map[pos]string{}
+	byField := map[string]map[string]string{}
+	seen := map[string]bool{}
+	var files []string
+	addFile := func(path string) {
+		if !seen[path] {
+			seen[path] = true
+			files = append(files, path)
+		}
+	}
+	for _, b := range bs {
+		tag := tags[[2]string{b.Function, b.Variable}]
+		if tag == "" || fileLanguage(b.File) != "Go" {
+			continue
+		}
+		if b.Optional || optionalType.MatchString(b.DataType) {
+			// Skip the other checks when the value is left out.
+			tag = "omitempty," + tag
+		}
+		if b.Field != "" {
+			dir := filepath.Dir(b.File)
+			if byField[dir] == nil {
+				byField[dir] = map[string]string{}
+				pkg, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+				for _, p := range pkg {
+					if !strings.HasSuffix(p, "_test.go") {
+						addFile(p)
+					}
+				}
+			}
+			byField[dir][b.Field] = tag
+			continue
+		}
+		if byPos[b.File] == nil {
+			byPos[b.File] = map[pos]string{}
+			addFile(b.File)
+		}
+		byPos[b.File][pos{b.Line, b.Column}] = tag
+	}
+	sort.Strings(files)
+
+	var patch strings.Builder
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		src := string(data)
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, path, src, parser.SkipObjectResolution)
+		if err != nil {
+			return "", err
+		}
+		owners := map[*ast.Field]string{}
+		ast.Inspect(f, func(n ast.Node) bool {
+			if ts, ok := n.(*ast.TypeSpec); ok {
+				if st, ok := ts.Type.(*ast.StructType); ok {
+					for _, field := range st.Fields.List {
+						owners[field] = ts.Name.Name
+					}
+				}
+			}
+			return true
+		})
+		tagFor := func(field *ast.Field) (string, bool) {
+			p := fset.Position(field.Pos())
+			if tag, ok := byPos[path][pos{p.Line, p.Column}]; ok {
+				return tag, true
+			}
+			for _, id := range field.Names {
+				if tag, ok := byField[filepath.Dir(path)][owners[field]+"."+id.Name]; ok {
+					return tag, true
+				}
+			}
+			return "", false
+		}
+		type edit struct {
+			start, end int
+			text       string
+		}
+		var edits []edit
+		ast.Inspect(f, func(n ast.Node) bool {
+			field, ok := n.(*ast.Field)
+			if !ok {
+				return true
+			}
+			tag, ok := tagFor(field)
+			if !ok || !taggable(types.ExprString(field.Type)) {
+				return true
+			}
+			if field.Tag == nil {
+				at := fset.Position(field.End()).Offset
+				edits = append(edits, edit{at, at, fmt.Sprintf(" `validate:%q`", tag)})
+				return true
+			}
+			raw, err := strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				return true
+			}
+			if _, ok := reflect.StructTag(raw).Lookup("validate"); ok {
+				return true
+			}
+			raw += fmt.Sprintf(" validate:%q", tag)
+			lit := "`" + raw + "`"
+			if strings.Contains(raw, "`") {
+				lit = strconv.Quote(raw)
+			}
+			edits = append(edits, edit{fset.Position(field.Tag.Pos()).Offset, fset.Position(field.Tag.End()).Offset, lit})
+			return true
+		})
+		if len(edits) == 0 {
+			continue
+		}
+		sort.Slice(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+		out := src
+		for _, e := range edits {
+			out = out[:e.start] + e.text + out[e.end:]
+		}
+		diff, err := unifiedDiff(path, src, out)
+		if err != nil {
+			return "", err
+		}
+		patch.WriteString(diff)
+	}
+	return patch.String(), nil
+}
+
+// taggable reports whether a field of Go type t can carry the tag generated
+// for it: a scalar, or a slice of scalars. Struct-typed fields are validated
+// through their own fields, and types such as time.Duration would be given a
+// string's length rules.
+func taggable(t string) bool {
+	t = strings.TrimPrefix(t, "*")
+	if m := listElem.FindStringSubmatch(t); m != nil {
+		t = m[1]
+	}
+	return scalarKind(t) != ""
+}
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+// unifiedDiff returns a unified diff from before to after. It only handles
+// edits that keep every line in place, which is all that tag rewriting does.
+func unifiedDiff(path, before, after string) (string, error) {
+	a, b := strings.Split(before, "\n"), strings.Split(after, "\n")
+	if len(a) != len(b) {
+		return "", fmt.Errorf("%s: rewriting tags changed the line count", path)
+	}
+	var changed []int
+	for i := range a {
+		if a[i] != b[i] {
+			changed = append(changed, i)
+		}
+	}
+	if len(changed) == 0 {
+		return "", nil
+	}
+	var s strings.Builder
+	name := strings.TrimPrefix(reportPath(path), "/")
+	fmt.Fprintf(&s, "--- a/%s\n+++ b/%s\n", name, name)
+	for i := 0; i < len(changed); {
+		start := max(changed[i]-diffContext, 0)
+		j := i
+		for j+1 < len(changed) && changed[j+1]-changed[j] <= 2*diffContext {
+			j++
+		}
+		end := min(changed[j]+diffContext+1, len(a))
+		if end == len(a) && a[end-1] == "" {
+			end-- // trailing newline, not a line
+		}
+		fmt.Fprintf(&s, "@@ -%d,%d +%d,%d @@\n", start+1, end-start, start+1, end-start)
+		for k := start; k < end; k++ {
+			if a[k] == b[k] {
+				fmt.Fprintf(&s, " %s\n", a[k])
+			} else {
+				fmt.Fprintf(&s, "-%s\n+%s\n", a[k], b[k])
+			}
+		}
+		i = j + 1
+	}
+	return s.String(), nil
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidatorTag(t *testing.T) {
+	cases := []struct {
+		e    BoundaryEntry
+		want string
+	}{
+		{BoundaryEntry{DataType: "string", MinLength: 1, MaxLength: 64}, "max=64,min=1"},
+		{BoundaryEntry{DataType: "enum", EnumValues: []string{"a", "b", "c"}}, "oneof=a b c"},
+		{BoundaryEntry{DataType: "string", EnumValues: []string{"dark blue", "a,b"}}, "oneof='dark blue' a0x2Cb"},
+		{BoundaryEntry{DataType: "int", MinValue: 1, MaxValue: 100}, "gte=1,lte=100"},
+		{BoundaryEntry{DataType: "int"}, ""},
+		{BoundaryEntry{DataType: "string", RegexPattern: "^x$"}, ""},
+		{BoundaryEntry{DataType: "uint", MaxValue: 10}, "lte=10"},
+		{BoundaryEntry{DataType: "float64", MinFloat: -0.5, MaxFloat: 1e6}, "gte=-0.5,lte=1e+06"},
+		{BoundaryEntry{DataType: "bool"}, ""},
+		{BoundaryEntry{DataType: "list", MaxItems: 3, UniqueItems: true, Items: &BoundaryEntry{DataType: "string", MaxLength: 8}}, "max=3,unique,dive,max=8"},
+	}
+	for _, c := range cases {
+		if got := ValidatorTag(c.e); got != c.want {
+			t.Errorf("ValidatorTag(%+v) = %q, want %q", c.e, got, c.want)
+		}
+	}
+}
+
+func TestValidatorTagPatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cfg.go")
+	mustWrite(t, path, "package main\n\n"+
+		"type Config struct {\n"+
+		"\tPort  int    `envconfig:\"port\"`\n"+
+		"\tHost  string `envconfig:\"host\"`\n"+
+		"\tLevel string `env:\"LOG_LEVEL\" validate:\"oneof=debug info\"`\n"+
+		"}\n")
+	bs := ScanFile(path, ".go")
+	if len(bs) != 3 {
+		t.Fatalf("want 3 boundaries, got %d", len(bs))
+	}
+	entries := []BoundaryEntry{
+		{ParamName: "PORT", DataType: "int", MinValue: 1, MaxValue: 65535},
+		{ParamName: "HOST", DataType: "string", MinLength: 1, MaxLength: 253, Format: "hostname"},
+		{ParamName: "LOG_LEVEL", DataType: "enum", EnumValues: []string{"warn"}},
+	}
+	patch, err := ValidatorTagPatch(bs, entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := strings.TrimPrefix(filepath.ToSlash(path), "/")
+	for _, want := range []string{
+		"--- a/" + name + "\n+++ b/" + name + "\n@@ -1,7 +1,7 @@\n",
+		"-\tPort  int    `envconfig:\"port\"`\n+\tPort  int    `envconfig:\"port\" validate:\"gte=1,lte=65535\"`\n",
+		"+\tHost  string `envconfig:\"host\" validate:\"max=253,min=1,hostname_rfc1123\"`\n",
+		" \tLevel string `env:\"LOG_LEVEL\" validate:\"oneof=debug info\"`\n",
+	} {
+		if !strings.Contains(patch, want) {
+			t.Errorf("patch missing %q:\n%s", want, patch)
+		}
+	}
+}
+
+func TestUnifiedDiffHunks(t *testing.T) {
+	var a []string
+	for i := 1; i <= 20; i++ {
+		a = append(a, "line")
+	}
+	before := strings.Join(a, "\n") + "\n"
+	a[1], a[17] = "two", "eighteen"
+	got, err := unifiedDiff("x.go", before, strings.Join(a, "\n")+"\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(got, "@@ -"); n != 2 {
+		t.Fatalf("want 2 hunks for distant edits, got %d:\n%s", n, got)
+	}
+	if !strings.Contains(got, "@@ -1,5 +1,5 @@") || !strings.Contains(got, "@@ -15,6 +15,6 @@") {
+		t.Errorf("bad hunk headers:\n%s", got)
+	}
+	if _, err := unifiedDiff("x.go", before, before+"extra\n"); err == nil {
+		t.Error("want an error when the line count changes")
+	}
+}
+
+func TestValidatorTagPatchRequestStruct(t *testing.T) {
+	dir := t.TempDir()
+	mustWrite(t, filepath.Join(dir, "models.go"), "package api\n\n"+
+		"type Page struct {\n"+
+		"\tLimit int `json:\"limit\"`\n"+
+		"}\n\n"+
+		"type CreateUser struct {\n"+
+		"\tPage\n"+
+		"\tName    string    `json:\"name\"`\n"+
+		"\tTags    []string  `json:\"tags\"`\n"+
+		"\tCreated time.Time `json:\"created\"`\n"+
+		"\tNick    *string   `json:\"nick\"`\n"+
+		"\tBio     string    `json:\"bio,omitempty\"`\n"+
+		"\tSecret  string    `json:\"-\"`\n"+
+		"}\n")
+	handler := filepath.Join(dir, "handler.go")
+	mustWrite(t, handler, "package api\n\n"+
+		"func create(w http.ResponseWriter, r *http.Request) {\n"+
+		"\tvar req CreateUser\n"+
+		"\tif err := json.NewDecoder(r.Body).Decode(&req); err != nil {\n"+
+		"\t\treturn\n"+
+		"\t}\n"+
+		"}\n")
+	bs := ScanFile(handler, ".go")
+	if len(bs) != 6 {
+		t.Fatalf("want 6 boundaries, got %d: %+v", len(bs), bs)
+	}
+	assertBoundary(t, bs[0], "limit", "http_body", "JSON Body")
+	assertBoundary(t, bs[1], "name", "http_body", "JSON Body")
+	if bs[0].Field != "Page.Limit" || bs[1].Function != "create" {
+		t.Errorf("got field %q in %q, want Page.Limit in create", bs[0].Field, bs[1].Function)
+	}
+
+	entries := EntriesFromBoundaries(bs)
+	entries[0].MinValue, entries[0].MaxValue = 1, 100
+	patch, err := ValidatorTagPatch(bs, entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"+\tLimit int `json:\"limit\" validate:\"gte=1,lte=100\"`\n",
+		"+\tName    string    `json:\"name\" validate:\"max=1024,min=1\"`\n",
+		"+\tTags    []string  `json:\"tags\" validate:\"dive,max=1024\"`\n",
+		" \tCreated time.Time `json:\"created\"`\n",
+		// Optional fields may be left out, so they are not required either.
+		"+\tNick    *string   `json:\"nick\" validate:\"omitempty,max=1024\"`\n",
+		"+\tBio     string    `json:\"bio,omitempty\" validate:\"omitempty,max=1024\"`\n",
+	} {
+		if !strings.Contains(patch, want) {
+			t.Errorf("patch missing %q:\n%s", want, patch)
+		}
+	}
+	if strings.Contains(patch, "handler.go") {
+		t.Errorf("the handler itself should not change:\n%s", patch)
+	}
+}